@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"strings"
+
+	"mix-eval-go/pkg/orchestrator"
+)
+
+// judgeFlags selects the judge of a run or rejudge
+type judgeFlags struct {
+	provider *string
+	model    *string
+	baseURL  *string
+	headers  *string
+	ensemble *string
+	vote     *string
+}
+
+// registerJudgeFlags adds the judge flags to a flag set; their defaults come
+// from the environment
+func registerJudgeFlags(flags *flag.FlagSet) *judgeFlags {
+	return &judgeFlags{
+		provider: flags.String("judge-provider", getEnv("JUDGE_PROVIDER", "gemini"), "Judge provider (gemini, anthropic, openai, openai-compatible; env JUDGE_PROVIDER)"),
+		model:    flags.String("judge-model", getEnv("JUDGE_MODEL", ""), "Judge model (default: the provider's standard judge model; env JUDGE_MODEL)"),
+		baseURL:  flags.String("judge-base-url", getEnv("JUDGE_BASE_URL", ""), "API root of an openai-compatible judge server, e.g. http://localhost:11434/v1 (env JUDGE_BASE_URL)"),
+		headers:  flags.String("judge-headers", getEnv("JUDGE_HEADERS", ""), "Extra HTTP headers for openai-compatible judges, e.g. X-Team=evals,X-Api-Version=2 (env JUDGE_HEADERS)"),
+		ensemble: flags.String("judge-ensemble", "", "Comma-separated provider:model judges whose verdicts are combined, e.g. gemini:gemini-3-pro-preview,anthropic:claude-sonnet-4-5-20250929,gemini:gemini-3-flash-preview"),
+		vote:     flags.String("judge-vote", "majority", "How an ensemble's verdicts are combined (majority, unanimous)"),
+	}
+}
+
+// configure sets the judge (or judge ensemble) and checks every judge has its
+// API key or server
+func (f *judgeFlags) configure(config *orchestrator.Config) {
+	config.GeminiAPIKey = getEnv("GEMINI_API_KEY", "")
+	config.AnthropicAPIKey = getEnv("ANTHROPIC_API_KEY", "")
+	config.OpenAIAPIKey = getEnv("OPENAI_API_KEY", "")
+	config.JudgeAPIKey = getEnv("JUDGE_API_KEY", "")
+	config.JudgeBaseURL = *f.baseURL
+
+	headers, err := parseHeaders(*f.headers)
+	if err != nil {
+		log.Fatalf("Invalid --judge-headers: %v", err)
+	}
+	config.JudgeHeaders = headers
+
+	judge, err := orchestrator.ParseJudgeSpec(*f.provider + ":" + *f.model)
+	if err != nil {
+		log.Fatalf("Invalid --judge-provider: %v", err)
+	}
+	config.Judge = judge
+
+	specs, err := orchestrator.ParseJudgeSpecs(*f.ensemble)
+	if err != nil {
+		log.Fatalf("Invalid --judge-ensemble: %v", err)
+	}
+	if len(specs) == 1 {
+		log.Fatal("--judge-ensemble needs at least 2 judges")
+	}
+	rule, err := orchestrator.ParseVoteRule(*f.vote)
+	if err != nil {
+		log.Fatalf("Invalid --judge-vote: %v", err)
+	}
+	config.JudgeEnsemble = specs
+	config.JudgeVote = rule
+
+	if err := orchestrator.ValidateJudges(*config); err != nil {
+		log.Fatalf("Judge configuration failed: %v", err)
+	}
+	if len(specs) > 0 {
+		slog.Info("using judge ensemble", "judges", *f.ensemble, "vote", rule)
+	} else {
+		slog.Info("using judge", "provider", judge.Provider, "model", judge.Model)
+	}
+}
+
+// parseHeaders parses comma-separated Name=value pairs
+func parseHeaders(spec string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid header %q (want Name=value)", pair)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
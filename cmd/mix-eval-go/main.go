@@ -5,11 +5,16 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
 
+	"mix-eval-go/pkg/convex"
 	"mix-eval-go/pkg/orchestrator"
 )
 
@@ -17,54 +22,137 @@ func main() {
 	// Auto-load .env file if present (silently ignore if missing)
 	_ = godotenv.Load()
 
+	if len(os.Args) > 1 && os.Args[1] == "rejudge" {
+		rejudgeMain(os.Args[2:])
+		return
+	}
+
 	// Parse command line flags
-	datasetName := flag.String("dataset", "", "Dataset name to run (required)")
+	datasetName := flag.String("dataset", "", "Convex dataset name or path to a JSON/JSONL/CSV task file (required)")
+	datasetColumns := flag.String("dataset-columns", "", "CSV column mapping, e.g. task_id=id,confirmed_task=prompt")
 	taskID := flag.String("task-id", "", "Specific task ID to run (optional, overrides index range)")
 	runID := flag.String("run-id", "", "Run ID for this evaluation")
 	startIndex := flag.Int("start-index", 0, "Start index for task range (inclusive)")
 	endIndex := flag.Int("end-index", -1, "End index for task range (inclusive, -1 for all)")
 	parallelism := flag.Int("parallel", 3, "Number of parallel tasks")
-	browserProvider := flag.String("browser-provider", "", "Browser provider (browserbase, brightdata, hyperbrowser, anchor)")
-	model := flag.String("model", "", "LLM model to use (overrides task default)")
-	maxSteps := flag.Int("max-steps", 0, "Maximum steps per task (0 for no limit)")
+	browserProvider := flag.String("browser-provider", "", "Browser provider, or comma-separated failover chain, e.g. browserbase,hyperbrowser,local (browserbase, brightdata, hyperbrowser, anchor, local-cdp, local); a task's browserProvider overrides it")
+	browserSessionLimit := flag.Int("browser-session-limit", 0, "Maximum live sessions per browser provider, warm ones included (0 for no limit)")
+	model := flag.String("model", "", "Mix agent model to use (a task's model field overrides it)")
+	maxSteps := flag.Int("max-steps", 0, "Maximum tool steps per task (0 for no limit; a task's maxSteps field overrides it)")
+	sinkNames := flag.String("sink", "convex", "Comma-separated result sinks (convex, local)")
+	outputDir := flag.String("output-dir", "runs", "Directory for local run artifacts")
+	shutdownGrace := flag.Duration("shutdown-grace", 2*time.Minute, "How long in-flight tasks may finish after SIGINT/SIGTERM before being cancelled")
+	taskTimeout := flag.Duration("task-timeout", 0, "Wall-clock limit per task, e.g. 15m (0 for no limit; a task's timeoutSeconds overrides it)")
+	infraRetries := flag.Int("infra-retries", 2, "Retries per task after infrastructure failures (Mix, browser provider, judge API)")
+	retryBackoff := flag.Duration("retry-backoff", 10*time.Second, "Initial backoff between infrastructure retries (doubles each retry)")
+	trials := flag.Int("trials", 1, "Independent trials per task (reports pass@k and pass^k when > 1)")
+	summaryFile := flag.String("summary-file", "", "Path for the JSON run summary (default <output-dir>/<run-id>/summary.json)")
+	traces := flag.Bool("traces", true, "Write every raw SSE event and the Mix message history of each task under <output-dir>/<run-id>/traces")
+	uploadTraces := flag.Bool("upload-traces", false, "Also upload task traces to Convex file storage and reference them in results")
+	resume := flag.Bool("resume", false, "Resume --run-id, skipping tasks its checkpoint ledger records as completed")
+	logFormat := flag.String("log-format", "text", "Log format for the console and per-task log files (text, json)")
+	judge := registerJudgeFlags(flag.CommandLine)
+	quiet := flag.Bool("quiet", false, "Only log task lifecycle events to the console (agent activity still goes to per-task log files)")
 	flag.Parse()
 
+	// Agent activity is logged at Debug; quiet mode keeps the console to lifecycle events
+	level := slog.LevelDebug
+	if *quiet {
+		level = slog.LevelInfo
+	}
+	handler, err := orchestrator.NewLogHandler(os.Stderr, *logFormat, level)
+	if err != nil {
+		log.Fatalf("Invalid --log-format: %v", err)
+	}
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+
 	if *datasetName == "" {
 		log.Fatal("--dataset is required")
 	}
+	if *trials < 1 {
+		log.Fatal("--trials must be at least 1")
+	}
+	if *browserSessionLimit < 0 {
+		log.Fatal("--browser-session-limit must not be negative")
+	}
+	if *maxSteps < 0 {
+		log.Fatal("--max-steps must not be negative")
+	}
+	if *resume && *runID == "" {
+		log.Fatal("--resume requires --run-id")
+	}
 
 	// Load configuration from environment
 	config := orchestrator.Config{
-		MixURL:          getEnv("MIX_AGENT_URL", "http://localhost:8088"),
-		ConvexURL:       getEnv("CONVEX_URL", ""),
-		ConvexSecretKey: getEnv("CONVEX_SECRET_KEY", ""),
-		BrowserbaseKey:  getEnv("BROWSERBASE_API_KEY", ""),
-		BrightdataUser:  getEnv("BRIGHTDATA_USER", ""),
-		BrightdataPass:  getEnv("BRIGHTDATA_PASS", ""),
-		GeminiAPIKey: getEnv("GEMINI_API_KEY", ""),
+		MixURL:               getEnv("MIX_AGENT_URL", "http://localhost:8088"),
+		ConvexURL:            getEnv("CONVEX_URL", ""),
+		ConvexSecretKey:      getEnv("CONVEX_SECRET_KEY", ""),
+		BrowserbaseKey:       getEnv("BROWSERBASE_API_KEY", ""),
+		BrowserbaseProjectID: getEnv("BROWSERBASE_PROJECT_ID", ""),
+		BrightdataUser:       getEnv("BRIGHTDATA_USER", ""),
+		BrightdataPass:       getEnv("BRIGHTDATA_PASS", getEnv("BRIGHTDATA_PASSWORD", "")),
+		HyperbrowserKey:      getEnv("HYPERBROWSER_API_KEY", ""),
+		AnchorKey:            getEnv("ANCHOR_BROWSER_API_KEY", getEnv("ANCHOR_API_KEY", "")),
+		LocalCDPEndpoints:    strings.Split(getEnv("LOCAL_CDP_ENDPOINTS", ""), ","),
+		BrowserProvider:      *browserProvider,
+		BrowserSessionLimit:  *browserSessionLimit,
+		OutputDir:            *outputDir,
+		Resume:               *resume,
+		Traces:               *traces || *uploadTraces,
+		UploadTraces:         *uploadTraces,
+		TaskTimeout:          *taskTimeout,
+		ShutdownGrace:        *shutdownGrace,
+		InfraRetries:         *infraRetries,
+		RetryBackoff:         *retryBackoff,
+		Trials:               *trials,
+		Model:                *model,
+		MaxSteps:             *maxSteps,
+		Logger:               logger,
+		LogFormat:            *logFormat,
+	}
+
+	columns, err := orchestrator.ParseColumnMapping(*datasetColumns)
+	if err != nil {
+		log.Fatalf("Invalid --dataset-columns: %v", err)
+	}
+	config.DatasetColumns = columns
+
+	sink, err := buildSink(*sinkNames, *outputDir, config)
+	if err != nil {
+		log.Fatalf("Invalid --sink: %v", err)
 	}
+	config.Sink = sink
 
-	// Validate required config
-	if config.ConvexURL == "" || config.ConvexSecretKey == "" {
+	// Validate required config (Convex is optional for local datasets)
+	if !orchestrator.IsTaskFile(*datasetName) && (config.ConvexURL == "" || config.ConvexSecretKey == "") {
 		log.Fatal("CONVEX_URL and CONVEX_SECRET_KEY are required")
 	}
-	if config.GeminiAPIKey == "" {
-		log.Fatal("GEMINI_API_KEY is required for judge evaluation")
+	if config.UploadTraces && (config.ConvexURL == "" || config.ConvexSecretKey == "") {
+		log.Fatal("--upload-traces requires CONVEX_URL and CONVEX_SECRET_KEY")
 	}
+	judge.configure(&config)
 
 	// Create orchestrator
 	orch := orchestrator.New(config)
+	if *browserProvider != "" {
+		if err := orch.CheckBrowserProviders(context.Background(), *browserProvider); err != nil {
+			log.Fatalf("Invalid --browser-provider: %v", err)
+		}
+	}
 
-	ctx := context.Background()
+	// The first SIGINT/SIGTERM drains in-flight tasks, a second one cancels them
+	ctx, stop := handleShutdownSignals(orch)
+	defer stop()
 
-	// Fetch tasks from Convex
-	fmt.Printf("Fetching dataset: %s\n", *datasetName)
+	// Fetch tasks from Convex or a local file
+	slog.Info("fetching dataset", "dataset", *datasetName)
 	tasks, err := orch.FetchTasks(ctx, *datasetName)
 	if err != nil {
 		log.Fatalf("Failed to fetch tasks: %v", err)
 	}
 
-	fmt.Printf("Found %d tasks\n", len(tasks))
+	slog.Info("dataset loaded", "tasks", len(tasks))
 
 	// Handle task-id or index range
 	if *taskID != "" {
@@ -81,9 +169,8 @@ func main() {
 			log.Fatalf("Task ID %s not found in dataset", *taskID)
 		}
 
-		fmt.Printf("Found task %s at index %d\n", *taskID, taskIndex)
+		slog.Info("processing single task", "task_id", *taskID, "index", taskIndex)
 		tasks = tasks[taskIndex : taskIndex+1]
-		fmt.Printf("Processing task: %s\n", *taskID)
 	} else {
 		// Apply index range filter
 		if *endIndex == -1 {
@@ -97,43 +184,129 @@ func main() {
 		}
 
 		tasks = tasks[*startIndex : *endIndex+1]
-		fmt.Printf("Processing tasks %d-%d (%d tasks)\n", *startIndex, *endIndex, len(tasks))
+		slog.Info("processing task range", "start_index", *startIndex, "end_index", *endIndex, "tasks", len(tasks))
 	}
 
 	// Set run ID on all tasks (auto-generate if not provided)
 	if *runID == "" {
 		*runID = fmt.Sprintf("run-%d", time.Now().Unix())
-		fmt.Printf("Auto-generated run ID: %s\n", *runID)
+		slog.Info("auto-generated run ID", "run_id", *runID)
 	}
 	for i := range tasks {
 		tasks[i].RunID = *runID
 	}
 
-	// Apply model override if specified
 	if *model != "" {
-		fmt.Printf("Overriding model to: %s\n", *model)
-		// Note: Model override would be implemented in orchestrator
+		slog.Info("overriding model", "model", *model)
 	}
-
-	// Apply max steps if specified
 	if *maxSteps > 0 {
-		fmt.Printf("Setting max steps: %d\n", *maxSteps)
-		// Note: Max steps would be implemented in orchestrator
+		slog.Info("setting max steps", "max_steps", *maxSteps)
 	}
 
-	// Apply browser provider if specified
 	if *browserProvider != "" {
-		fmt.Printf("Using browser provider: %s\n", *browserProvider)
-		// Note: Browser provider would be passed to orchestrator config
+		slog.Info("using browser provider", "provider", *browserProvider)
 	}
 
 	// Run tasks in parallel
-	fmt.Printf("Starting evaluation with parallelism=%d\n", *parallelism)
-	if err := orch.RunMultipleTasks(ctx, tasks, *parallelism); err != nil {
-		log.Fatalf("Execution failed: %v", err)
+	slog.Info("starting evaluation", "run_id", *runID, "parallelism", *parallelism)
+	summary, runErr := orch.RunMultipleTasks(ctx, tasks, *parallelism)
+	if summary == nil {
+		log.Fatalf("Execution failed: %v", runErr)
 	}
 
-	fmt.Println("✅ All tasks completed successfully")
+	if *summaryFile == "" {
+		*summaryFile = orchestrator.SummaryPath(*outputDir, *runID)
+		// A partial summary must not replace the full one of an earlier attempt
+		if summary.Partial {
+			*summaryFile = strings.TrimSuffix(*summaryFile, ".json") + ".partial.json"
+		}
+	}
+	reportRun(summary, runErr, *summaryFile)
+}
+
+// reportRun prints the run summary, writes it as JSON and exits non-zero
+// when the run failed
+func reportRun(summary *orchestrator.RunSummary, runErr error, summaryFile string) {
+	summary.WriteTable(os.Stdout)
+	if err := summary.WriteJSON(summaryFile); err != nil {
+		slog.Warn("failed to write run summary", "error", err)
+	} else {
+		slog.Info("run summary written", "path", summaryFile)
+	}
+
+	switch {
+	case runErr != nil:
+		log.Fatalf("Execution failed: %v", runErr)
+	case summary.Total > 0 && summary.AllErrored():
+		log.Fatalf("❌ All %d tasks errored", summary.Errored)
+	case summary.Errored > 0:
+		slog.Warn("run completed with errored tasks", "errored", summary.Errored)
+	default:
+		slog.Info("all tasks completed successfully")
+	}
+}
+
+// buildSink creates the result sink(s) named in a comma-separated list
+func buildSink(names, outputDir string, config orchestrator.Config) (orchestrator.ResultSink, error) {
+	var sinks orchestrator.MultiSink
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "convex":
+			if config.ConvexURL == "" || config.ConvexSecretKey == "" {
+				return nil, fmt.Errorf("convex sink requires CONVEX_URL and CONVEX_SECRET_KEY")
+			}
+			sinks = append(sinks, convex.NewClient(config.ConvexURL, config.ConvexSecretKey))
+		case "local":
+			localSink, err := orchestrator.NewLocalSink(outputDir)
+			if err != nil {
+				return nil, err
+			}
+			sinks = append(sinks, localSink)
+			slog.Info("writing results locally", "output_dir", outputDir)
+		case "":
+			continue
+		default:
+			return nil, fmt.Errorf("unknown sink: %s", name)
+		}
+	}
+
+	switch len(sinks) {
+	case 0:
+		return nil, fmt.Errorf("at least one sink is required")
+	case 1:
+		return sinks[0], nil
+	default:
+		return sinks, nil
+	}
+}
+
+// handleShutdownSignals returns a context cancelled by the first SIGINT/SIGTERM.
+// A second signal aborts in-flight tasks without waiting for the grace period
+// and restores the default handling, so a third one kills the process.
+func handleShutdownSignals(orch *orchestrator.Orchestrator) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			slog.Warn("received signal, finishing in-flight tasks (signal again to force stop)", "signal", sig)
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+		if sig, ok := <-signals; ok {
+			slog.Warn("received signal, cancelling in-flight tasks (signal again to kill)", "signal", sig)
+			orch.Abort()
+			signal.Stop(signals)
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		cancel()
+	}
 }
 
 func getEnv(key, defaultValue string) string {
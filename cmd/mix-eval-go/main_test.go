@@ -0,0 +1,71 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mix-eval-go/pkg/convex"
+	"mix-eval-go/pkg/orchestrator"
+)
+
+func TestBuildSink(t *testing.T) {
+	withConvex := orchestrator.Config{ConvexURL: "https://example.convex.cloud", ConvexSecretKey: "secret"}
+
+	tests := []struct {
+		name    string
+		names   string
+		config  orchestrator.Config
+		wantErr string
+		check   func(t *testing.T, sink orchestrator.ResultSink)
+	}{
+		{
+			name:   "convex only",
+			names:  "convex",
+			config: withConvex,
+			check: func(t *testing.T, sink orchestrator.ResultSink) {
+				if _, ok := sink.(*convex.Client); !ok {
+					t.Errorf("Expected a Convex client, got %T", sink)
+				}
+			},
+		},
+		{
+			name:  "local only",
+			names: "local",
+			check: func(t *testing.T, sink orchestrator.ResultSink) {
+				if _, ok := sink.(*orchestrator.LocalSink); !ok {
+					t.Errorf("Expected a local sink, got %T", sink)
+				}
+			},
+		},
+		{
+			name:   "both with spaces",
+			names:  "convex, local",
+			config: withConvex,
+			check: func(t *testing.T, sink orchestrator.ResultSink) {
+				if multi, ok := sink.(orchestrator.MultiSink); !ok || len(multi) != 2 {
+					t.Errorf("Expected a fan-out to 2 sinks, got %#v", sink)
+				}
+			},
+		},
+		{name: "convex without credentials", names: "convex", wantErr: "CONVEX_URL and CONVEX_SECRET_KEY"},
+		{name: "unknown sink", names: "local,s3", wantErr: "unknown sink: s3"},
+		{name: "no sinks", names: " , ", wantErr: "at least one sink is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink, err := buildSink(tt.names, filepath.Join(t.TempDir(), "results"), tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildSink failed: %v", err)
+			}
+			tt.check(t, sink)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"time"
+
+	"mix-eval-go/pkg/orchestrator"
+)
+
+// rejudgeMain re-evaluates a finished run with the current judge, from its
+// local traces or its results in Convex, without rerunning the agent
+func rejudgeMain(args []string) {
+	flags := flag.NewFlagSet("rejudge", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s rejudge --run-id <run> [flags]\n\nRe-evaluates the traces saved under <output-dir>/<run-id>/traces, or the run's results in Convex, and saves the verdicts under a new run ID.\n\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	runID := flags.String("run-id", "", "Run to judge again (required)")
+	from := flags.String("from", "local", "Where the run is stored: local (traces under --output-dir) or convex (saved results)")
+	newRunID := flags.String("new-run-id", "", "Run ID for the new verdicts (default <run-id>-rejudge-<unix time>)")
+	outputDir := flags.String("output-dir", "runs", "Directory holding local run artifacts")
+	sinkNames := flags.String("sink", "", "Comma-separated result sinks (convex, local; default: where the run is stored)")
+	parallelism := flags.Int("parallel", 3, "Number of tasks judged in parallel")
+	infraRetries := flags.Int("infra-retries", 2, "Retries per task after judge API failures")
+	retryBackoff := flags.Duration("retry-backoff", 10*time.Second, "Initial backoff between judge API retries (doubles each retry)")
+	summaryFile := flags.String("summary-file", "", "Path for the JSON run summary (default <output-dir>/<new-run-id>/summary.json)")
+	judge := registerJudgeFlags(flags)
+	logFormat := flags.String("log-format", "text", "Log format for the console and per-task log files (text, json)")
+	_ = flags.Parse(args)
+
+	handler, err := orchestrator.NewLogHandler(os.Stderr, *logFormat, slog.LevelInfo)
+	if err != nil {
+		log.Fatalf("Invalid --log-format: %v", err)
+	}
+	logger := slog.New(handler)
+	slog.SetDefault(logger)
+
+	if *runID == "" {
+		log.Fatal("--run-id is required")
+	}
+	if *newRunID == "" {
+		*newRunID = fmt.Sprintf("%s-rejudge-%d", *runID, time.Now().Unix())
+		slog.Info("auto-generated run ID", "run_id", *newRunID)
+	}
+	if *newRunID == *runID {
+		log.Fatal("--new-run-id must differ from --run-id")
+	}
+	if *from != "local" && *from != "convex" {
+		log.Fatalf("Invalid --from: %s (must be local or convex)", *from)
+	}
+	if *sinkNames == "" {
+		*sinkNames = *from
+	}
+
+	config := orchestrator.Config{
+		MixURL:          getEnv("MIX_AGENT_URL", "http://localhost:8088"),
+		ConvexURL:       getEnv("CONVEX_URL", ""),
+		ConvexSecretKey: getEnv("CONVEX_SECRET_KEY", ""),
+		OutputDir:       *outputDir,
+		InfraRetries:    *infraRetries,
+		RetryBackoff:    *retryBackoff,
+		Logger:          logger,
+		LogFormat:       *logFormat,
+	}
+	judge.configure(&config)
+	if *from == "convex" && (config.ConvexURL == "" || config.ConvexSecretKey == "") {
+		log.Fatal("--from convex requires CONVEX_URL and CONVEX_SECRET_KEY")
+	}
+
+	sink, err := buildSink(*sinkNames, *outputDir, config)
+	if err != nil {
+		log.Fatalf("Invalid --sink: %v", err)
+	}
+	config.Sink = sink
+
+	orch := orchestrator.New(config)
+	ctx, stop := handleShutdownSignals(orch)
+	defer stop()
+
+	var executions []orchestrator.StoredExecution
+	if *from == "convex" {
+		executions, err = orch.FetchRunExecutions(ctx, *runID)
+	} else {
+		executions, err = orchestrator.LoadTraces(*outputDir, *runID)
+	}
+	if err != nil {
+		log.Fatalf("Failed to load run %s: %v", *runID, err)
+	}
+	if len(executions) == 0 {
+		log.Fatalf("No judgeable executions found for run %s", *runID)
+	}
+	slog.Info("run loaded", "run_id", *runID, "from", *from, "tasks", len(executions))
+
+	slog.Info("starting rejudge", "run_id", *newRunID, "source_run_id", *runID, "parallelism", *parallelism)
+	summary, runErr := orch.Rejudge(ctx, executions, *newRunID, *parallelism)
+	if summary == nil {
+		log.Fatalf("Rejudge failed: %v", runErr)
+	}
+
+	if *summaryFile == "" {
+		*summaryFile = orchestrator.SummaryPath(*outputDir, *newRunID)
+	}
+	reportRun(summary, runErr, *summaryFile)
+}
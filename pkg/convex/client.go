@@ -38,18 +38,71 @@ type Task struct {
 	OutputSchema    map[string]interface{} `json:"outputSchema,omitempty"`
 	BrowserProvider string                 `json:"browserProvider,omitempty"`
 	Category        string                 `json:"category,omitempty"`
+	TimeoutSeconds  int                    `json:"timeoutSeconds,omitempty"`
+	Trial           int                    `json:"trial,omitempty"` // 1-based; 0 when the run has no trials
+	Model           string                 `json:"model,omitempty"`
+	MaxSteps        int                    `json:"maxSteps,omitempty"`
 }
 
 // TaskResult represents evaluation result
 type TaskResult struct {
 	RunID                string                   `json:"runId"`
 	TaskID               string                   `json:"taskId"`
+	Trial                int                      `json:"trial,omitempty"`
+	Category             string                   `json:"category,omitempty"`
 	Task                 string                   `json:"task"`
 	ToolCalls            []ToolCall               `json:"toolCalls"`
 	ScreenshotStorageIDs []string                 `json:"screenshotStorageIds"`
 	FinalResponse        string                   `json:"finalResultResponse"`
 	Evaluation           *Evaluation              `json:"comprehensiveJudgeEvaluation"`
 	CompleteHistory      []map[string]interface{} `json:"completeHistory,omitempty"`
+	Attempts             int                      `json:"attempts,omitempty"`
+	InfraError           string                   `json:"infraError,omitempty"`
+	DurationMs           int64                    `json:"durationMs,omitempty"`
+	StreamReconnects     int                      `json:"streamReconnects,omitempty"`
+	Model                string                   `json:"model,omitempty"`
+	MaxSteps             int                      `json:"maxSteps,omitempty"`
+	StepLimitReached     bool                     `json:"stepLimitReached,omitempty"`
+	BrowserProvider      string                   `json:"browserProvider,omitempty"`
+	AgentUsage           *TokenUsage              `json:"agentUsage,omitempty"`
+	JudgeUsage           *TokenUsage              `json:"judgeUsage,omitempty"`
+	Trace                *TraceRef                `json:"trace,omitempty"`
+	TimeToFirstActionMs  int64                    `json:"timeToFirstActionMs,omitempty"`
+	AgentDurationMs      int64                    `json:"agentDurationMs,omitempty"`
+	JudgeDurationMs      int64                    `json:"judgeDurationMs,omitempty"`
+	// SourceRunID is set on rejudged results to the run whose executions were judged again
+	SourceRunID string `json:"sourceRunId,omitempty"`
+	// CompleteHistoryStorageID replaces CompleteHistory when results are saved
+	// to Convex, since a long history can outgrow a Convex document
+	CompleteHistoryStorageID string `json:"completeHistoryStorageId,omitempty"`
+}
+
+// TraceRef locates a task's raw trace: every SSE event and the Mix message history
+type TraceRef struct {
+	Path              string `json:"path,omitempty"` // local directory
+	Events            int    `json:"events"`
+	EventsStorageID   string `json:"eventsStorageId,omitempty"`
+	MessagesStorageID string `json:"messagesStorageId,omitempty"`
+}
+
+// TokenUsage counts LLM tokens and what they cost
+type TokenUsage struct {
+	Calls        int     `json:"calls,omitempty"`
+	InputTokens  int64   `json:"inputTokens"`
+	OutputTokens int64   `json:"outputTokens"`
+	CostUSD      float64 `json:"costUsd"`
+	// Unpriced is set when some calls used a model without a known price,
+	// so CostUSD is a lower bound
+	Unpriced bool `json:"unpriced,omitempty"`
+}
+
+// Add accumulates other into u
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.Calls += other.Calls
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CostUSD += other.CostUSD
+	u.Unpriced = u.Unpriced || other.Unpriced
 }
 
 // ToolCall represents a tool execution
@@ -58,6 +111,10 @@ type ToolCall struct {
 	Args     string `json:"args,omitempty"`
 	Result   string `json:"result"`
 	IsError  bool   `json:"is_error"`
+	// StartedAt and EndedAt are Unix milliseconds, as seen on the event stream
+	StartedAt  int64 `json:"started_at,omitempty"`
+	EndedAt    int64 `json:"ended_at,omitempty"`
+	DurationMs int64 `json:"duration_ms,omitempty"`
 }
 
 // Evaluation represents judge evaluation
@@ -69,9 +126,23 @@ type Evaluation struct {
 	ImpossibleTask  bool     `json:"impossible_task"`
 	ReachedCaptcha  bool     `json:"reached_captcha"`
 	JudgeTraceID    string   `json:"judge_trace_id,omitempty"`
+	// JudgeProvider and JudgeModel identify the judge that produced the verdict
+	// (provider "ensemble" lists the ensemble's judges as the model)
+	JudgeProvider string `json:"judge_provider,omitempty"`
+	JudgeModel    string `json:"judge_model,omitempty"`
+	// NeedsHumanReview flags verdicts the judges of an ensemble disagreed on
+	NeedsHumanReview bool `json:"needs_human_review,omitempty"`
 	ComprehensiveEval map[string]interface{} `json:"comprehensive_evaluation,omitempty"`
 }
 
+// Error categories recorded by the runner itself (the judge adds its own)
+const (
+	// ErrorCategoryTimeout marks an evaluation whose agent run hit the task time limit
+	ErrorCategoryTimeout = "timeout"
+	// ErrorCategoryInfra marks a task that could not run because of Mix, browser provider or judge API failures
+	ErrorCategoryInfra = "infra_error"
+)
+
 // FetchTestCase fetches tasks from Convex
 func (c *Client) FetchTestCase(ctx context.Context, testCaseName string) ([]Task, error) {
 	url := fmt.Sprintf("%s/api/getTestCase", c.baseURL)
@@ -136,6 +207,81 @@ func (c *Client) SaveTaskResult(ctx context.Context, result *TaskResult) error {
 	return nil
 }
 
+// FetchRunResults fetches the saved results of a run
+func (c *Client) FetchRunResults(ctx context.Context, runID string) ([]TaskResult, error) {
+	url := fmt.Sprintf("%s/api/getRunResults", c.baseURL)
+
+	body, _ := json.Marshal(map[string]string{"runId": runID})
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch run results failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var results []TaskResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("decode run results failed: %w", err)
+	}
+	return results, nil
+}
+
+// DownloadFile fetches a file from Convex storage by its storage ID
+func (c *Client) DownloadFile(ctx context.Context, storageID string) ([]byte, error) {
+	url := fmt.Sprintf("%s/api/getStorageUrl", c.baseURL)
+
+	body, _ := json.Marshal(map[string]string{"storageId": storageID})
+
+	req, _ := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("get storage URL failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var result struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode storage URL failed: %w", err)
+	}
+	if result.URL == "" {
+		return nil, fmt.Errorf("storage file %s not found", storageID)
+	}
+
+	fileReq, err := http.NewRequestWithContext(ctx, "GET", result.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	fileResp, err := c.client.Do(fileReq)
+	if err != nil {
+		return nil, fmt.Errorf("download file failed: %w", err)
+	}
+	defer fileResp.Body.Close()
+
+	if fileResp.StatusCode != 200 {
+		return nil, fmt.Errorf("download file failed: status %d", fileResp.StatusCode)
+	}
+	return io.ReadAll(fileResp.Body)
+}
+
 // UploadScreenshots uploads screenshots to Convex storage
 func (c *Client) UploadScreenshots(ctx context.Context, screenshots [][]byte) ([]string, error) {
 	var storageIDs []string
@@ -146,7 +292,7 @@ func (c *Client) UploadScreenshots(ctx context.Context, screenshots [][]byte) ([
 			continue
 		}
 
-		storageID, err := c.uploadToStorage(ctx, uploadURL, screenshot)
+		storageID, err := c.uploadToStorage(ctx, uploadURL, screenshot, "image/jpeg")
 		if err != nil {
 			continue
 		}
@@ -157,6 +303,19 @@ func (c *Client) UploadScreenshots(ctx context.Context, screenshots [][]byte) ([
 	return storageIDs, nil
 }
 
+// UploadFile stores data in Convex file storage and returns its storage ID
+func (c *Client) UploadFile(ctx context.Context, data []byte, contentType string) (string, error) {
+	uploadURL, err := c.getUploadURL(ctx)
+	if err != nil {
+		return "", fmt.Errorf("get upload URL failed: %w", err)
+	}
+	storageID, err := c.uploadToStorage(ctx, uploadURL, data, contentType)
+	if err != nil {
+		return "", fmt.Errorf("upload failed: %w", err)
+	}
+	return storageID, nil
+}
+
 func (c *Client) getUploadURL(ctx context.Context) (string, error) {
 	url := fmt.Sprintf("%s/api/generateUploadUrl", c.baseURL)
 
@@ -181,9 +340,9 @@ func (c *Client) getUploadURL(ctx context.Context) (string, error) {
 	return result.UploadURL, nil
 }
 
-func (c *Client) uploadToStorage(ctx context.Context, uploadURL string, data []byte) (string, error) {
+func (c *Client) uploadToStorage(ctx context.Context, uploadURL string, data []byte, contentType string) (string, error) {
 	req, _ := http.NewRequestWithContext(ctx, "POST", uploadURL, bytes.NewBuffer(data))
-	req.Header.Set("Content-Type", "image/jpeg")
+	req.Header.Set("Content-Type", contentType)
 
 	resp, err := c.client.Do(req)
 	if err != nil {
@@ -0,0 +1,137 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/recreate-run/mix-go-sdk/models/operations"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// taskModel returns the agent model for a task. "" runs the task on the model
+// Mix was configured with before this orchestrator first overrode it.
+func (o *Orchestrator) taskModel(task convex.Task) string {
+	if task.Model != "" {
+		return task.Model
+	}
+	return o.config.Model
+}
+
+// taskMaxSteps returns the tool-step cap for a task (0 for no limit)
+func (o *Orchestrator) taskMaxSteps(task convex.Task) int {
+	if task.MaxSteps > 0 {
+		return task.MaxSteps
+	}
+	return o.config.MaxSteps
+}
+
+// modelLock coordinates the Mix agent model across parallel tasks. Mix only
+// exposes the model as a global preference, not per session, so tasks using
+// the same model (Mix's own default included) run together and a task needing
+// another model waits until they have finished.
+type modelLock struct {
+	mu     sync.Mutex
+	active string // model set in Mix preferences; "" is Mix's own default
+	users  int
+	// idle is closed when the last user of the active model releases it
+	idle chan struct{}
+	// defaultModel is Mix's configured model, read before the first override
+	// so default-model tasks can restore it ("" when Mix reported none)
+	defaultModel *string
+}
+
+// acquireModel sets the Mix agent model for a task ("" for Mix's own
+// default), waiting while tasks on another model are running. The returned
+// func must be called when the task no longer needs the model.
+func (o *Orchestrator) acquireModel(ctx context.Context, model string) (func(), error) {
+	l := &o.models
+	for {
+		l.mu.Lock()
+		if l.users == 0 || l.active == model {
+			break
+		}
+		idle := l.idle
+		l.mu.Unlock()
+
+		select {
+		case <-idle:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	defer l.mu.Unlock()
+
+	if l.active != model {
+		if err := o.switchModel(ctx, model); err != nil {
+			return nil, err
+		}
+		l.active = model
+	}
+	if l.users == 0 {
+		l.idle = make(chan struct{})
+	}
+	l.users++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			if l.users--; l.users == 0 {
+				close(l.idle)
+			}
+		})
+	}, nil
+}
+
+// switchModel updates Mix's agent model preference; the caller holds the lock.
+// Before the first override it records Mix's own model so that "" can
+// restore it.
+func (o *Orchestrator) switchModel(ctx context.Context, model string) error {
+	l := &o.models
+	if l.defaultModel == nil {
+		prefs, err := o.mixClient.Preferences.GetPreferences(ctx)
+		if err != nil {
+			return fmt.Errorf("read mix preferences failed: %w", err)
+		}
+		current := ""
+		if prefs.Object != nil && prefs.Object.Preferences != nil && prefs.Object.Preferences.MainAgentModel != nil {
+			current = *prefs.Object.Preferences.MainAgentModel
+		}
+		l.defaultModel = &current
+	}
+
+	target := model
+	if target == "" {
+		if target = *l.defaultModel; target == "" {
+			return fmt.Errorf("cannot restore mix's default agent model: mix reported none")
+		}
+	}
+	_, err := o.mixClient.Preferences.UpdatePreferences(ctx, operations.UpdatePreferencesRequest{
+		MainAgentModel: &target,
+	})
+	if err != nil {
+		return fmt.Errorf("update mix preferences failed: %w", err)
+	}
+	return nil
+}
+
+// restoreModel sets Mix's agent model back to the one it was configured with.
+// The preference is account-wide, so an override must not outlive the run
+// that made it. While tasks still hold the override it is left to the last
+// of their entry points to return.
+func (o *Orchestrator) restoreModel(ctx context.Context) {
+	l := &o.models
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active == "" || l.users > 0 {
+		return
+	}
+	if err := o.switchModel(ctx, ""); err != nil {
+		o.logger.Warn("restoring mix agent model failed", "model", *l.defaultModel, "error", err)
+		return
+	}
+	l.active = ""
+}
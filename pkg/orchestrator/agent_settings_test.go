@@ -0,0 +1,180 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recreate-run/mix-go-sdk"
+
+	"mix-eval-go/pkg/convex"
+	"mix-eval-go/pkg/sse"
+)
+
+func TestCollectEventsStopsAtStepLimit(t *testing.T) {
+	events := make(chan sse.Event, 20)
+	for i := 1; i <= 3; i++ {
+		events <- sse.Event{Type: "tool_use_start", Data: fmt.Sprintf(`{"id":"t%d","name":"browser_click"}`, i)}
+		events <- sse.Event{Type: "tool_execution_start", Data: fmt.Sprintf(`{"toolCallId":"t%d"}`, i)}
+		events <- sse.Event{Type: "tool_execution_complete", Data: fmt.Sprintf(`{"toolCallId":"t%d","success":true}`, i)}
+	}
+	close(events)
+
+	stops := 0
+	o := &Orchestrator{}
+	toolCalls, _ := o.collectEvents(slog.Default(), events, 2, func() { stops++ })
+
+	if stops != 1 {
+		t.Errorf("Expected stop to be called once, got %d", stops)
+	}
+	if len(toolCalls) != 2 {
+		t.Errorf("Expected the 2 tool calls within the limit to be recorded, got %d", len(toolCalls))
+	}
+}
+
+func TestCollectEventsWithinStepLimit(t *testing.T) {
+	events := make(chan sse.Event, 10)
+	events <- sse.Event{Type: "tool_use_start", Data: `{"id":"t1","name":"browser_navigate"}`}
+	events <- sse.Event{Type: "tool_execution_start", Data: `{"toolCallId":"t1"}`}
+	events <- sse.Event{Type: "tool_execution_complete", Data: `{"toolCallId":"t1","success":true}`}
+	events <- sse.Event{Type: "complete", Data: `{"type":"complete"}`}
+	close(events)
+
+	o := &Orchestrator{}
+	o.collectEvents(slog.Default(), events, 2, func() { t.Error("stop called within the step limit") })
+}
+
+func TestTaskModelAndMaxStepsPrecedence(t *testing.T) {
+	o := &Orchestrator{config: Config{Model: "run-model", MaxSteps: 50}}
+
+	task := convex.Task{ID: "a"}
+	if o.taskModel(task) != "run-model" || o.taskMaxSteps(task) != 50 {
+		t.Errorf("Expected run defaults, got %q/%d", o.taskModel(task), o.taskMaxSteps(task))
+	}
+	task.Model, task.MaxSteps = "task-model", 10
+	if o.taskModel(task) != "task-model" || o.taskMaxSteps(task) != 10 {
+		t.Errorf("Expected task overrides, got %q/%d", o.taskModel(task), o.taskMaxSteps(task))
+	}
+}
+
+// stubMixPreferences serves Mix's preferences API and records every model set
+func stubMixPreferences(t *testing.T) (*mix.Mix, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var updates []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/preferences" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"available_providers":{},"preferences":{"main_agent_model":"mix-default"}}`))
+			return
+		}
+		var req struct {
+			MainAgentModel string `json:"main_agent_model"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		updates = append(updates, req.MainAgentModel)
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return mix.New(srv.URL), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), updates...)
+	}
+}
+
+func TestAcquireModel(t *testing.T) {
+	client, updates := stubMixPreferences(t)
+	o := &Orchestrator{mixClient: client}
+	ctx := context.Background()
+
+	// Default-model tasks share Mix's own model without touching preferences
+	releaseDefault, err := o.acquireModel(ctx, "")
+	if err != nil {
+		t.Fatalf("acquireModel failed: %v", err)
+	}
+	if got := updates(); len(got) != 0 {
+		t.Errorf("Expected no preference update for the default model, got %v", got)
+	}
+
+	// An override waits for the default-model task to finish
+	acquired := make(chan func())
+	go func() {
+		release, err := o.acquireModel(ctx, "override-model")
+		if err != nil {
+			t.Errorf("acquireModel failed: %v", err)
+		}
+		acquired <- release
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("Override should wait while a default-model task runs")
+	case <-time.After(50 * time.Millisecond):
+	}
+	releaseDefault()
+	var releaseOverride func()
+	select {
+	case releaseOverride = <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("Override did not proceed after the default-model task finished")
+	}
+
+	// A default-model task waits for the override, gives up with its context,
+	// and restores Mix's own model once the override is released
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := o.acquireModel(timeoutCtx, ""); err == nil {
+		t.Error("Expected acquireModel to give up when its context ends")
+	}
+	releaseOverride()
+	releaseDefault, err = o.acquireModel(ctx, "")
+	if err != nil {
+		t.Fatalf("acquireModel failed: %v", err)
+	}
+	releaseDefault()
+
+	if got := updates(); len(got) != 2 || got[0] != "override-model" || got[1] != "mix-default" {
+		t.Errorf("Expected the override then Mix's own model to be set, got %v", got)
+	}
+}
+
+func TestRestoreModel(t *testing.T) {
+	client, updates := stubMixPreferences(t)
+	o := &Orchestrator{mixClient: client, logger: slog.Default()}
+	ctx := context.Background()
+
+	// Nothing to restore before a task overrides the model
+	o.restoreModel(ctx)
+	if got := updates(); len(got) != 0 {
+		t.Errorf("Expected no preference update without an override, got %v", got)
+	}
+
+	release, err := o.acquireModel(ctx, "override-model")
+	if err != nil {
+		t.Fatalf("acquireModel failed: %v", err)
+	}
+	// A run ending while another task still holds the override leaves it to that task
+	o.restoreModel(ctx)
+	if got := updates(); len(got) != 1 {
+		t.Errorf("Expected the override to stay while in use, got %v", got)
+	}
+
+	release()
+	o.restoreModel(ctx)
+	o.restoreModel(ctx)
+	if got := updates(); len(got) != 2 || got[1] != "mix-default" {
+		t.Errorf("Expected Mix's own model to be restored once, got %v", got)
+	}
+}
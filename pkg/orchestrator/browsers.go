@@ -0,0 +1,262 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+	"mix-eval-go/pkg/providers"
+)
+
+// LocalBrowser names Mix's own browser in a provider chain
+const LocalBrowser = "local"
+
+// unhealthyProviderCooldown is how long a provider that failed its health
+// check is moved to the back of provider chains
+const unhealthyProviderCooldown = time.Minute
+
+// ParseBrowserProviders splits a comma-separated provider chain, e.g.
+// "browserbase,hyperbrowser,local"
+func ParseBrowserProviders(chain string) []string {
+	var names []string
+	for _, name := range strings.Split(chain, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// taskBrowserProviders returns the provider chain for a task, falling back to
+// the run-level default (empty runs Mix's local browser)
+func (o *Orchestrator) taskBrowserProviders(task convex.Task) []string {
+	if task.BrowserProvider != "" {
+		return ParseBrowserProviders(task.BrowserProvider)
+	}
+	return ParseBrowserProviders(o.config.BrowserProvider)
+}
+
+// newBrowserRegistry builds the browser providers from the configured credentials
+func newBrowserRegistry(config Config) *providers.Registry {
+	return providers.NewRegistry(
+		providers.NewBrowserbase(providers.BrowserbaseOptions{
+			APIKey:    config.BrowserbaseKey,
+			ProjectID: config.BrowserbaseProjectID,
+		}),
+		providers.NewBrightdata(providers.BrightdataOptions{
+			Username: config.BrightdataUser,
+			Password: config.BrightdataPass,
+		}),
+		providers.NewHyperbrowser(providers.HyperbrowserOptions{
+			APIKey: config.HyperbrowserKey,
+		}),
+		providers.NewAnchorBrowser(providers.AnchorBrowserOptions{
+			APIKey: config.AnchorKey,
+		}),
+		providers.NewLocalCDP(providers.LocalCDPOptions{
+			Endpoints: config.LocalCDPEndpoints,
+		}),
+	)
+}
+
+// CheckBrowserProviders validates a provider chain. Unknown providers are an
+// error; providers that fail their health check are skipped in favour of the
+// rest of the chain, and only an error when no provider is usable.
+func (o *Orchestrator) CheckBrowserProviders(ctx context.Context, chain string) error {
+	names := ParseBrowserProviders(chain)
+	for _, name := range names {
+		if name == LocalBrowser {
+			continue
+		}
+		if _, err := o.browsers.Get(providers.BrowserProvider(name)); err != nil {
+			return err
+		}
+	}
+
+	var failures []error
+	for _, name := range names {
+		if name == LocalBrowser {
+			return nil
+		}
+		if err := o.checkBrowserProvider(ctx, name); err != nil {
+			failures = append(failures, err)
+			continue
+		}
+		if len(failures) > 0 {
+			o.logger.Warn("falling back to browser provider", "provider", name, "error", errors.Join(failures...))
+		}
+		return nil
+	}
+	return errors.Join(failures...)
+}
+
+// checkBrowserProvider runs a provider's health check and records the result
+func (o *Orchestrator) checkBrowserProvider(ctx context.Context, name string) error {
+	provider, err := o.browsers.Get(providers.BrowserProvider(name))
+	if err == nil {
+		err = provider.HealthCheck(ctx)
+	}
+
+	o.healthMu.Lock()
+	defer o.healthMu.Unlock()
+	if err != nil {
+		if o.unhealthy == nil {
+			o.unhealthy = make(map[string]time.Time)
+		}
+		o.unhealthy[name] = time.Now()
+		return err
+	}
+	delete(o.unhealthy, name)
+	return nil
+}
+
+// orderBrowserProviders moves providers that recently failed their health
+// check to the back of the chain, so they are only tried as a last resort
+func (o *Orchestrator) orderBrowserProviders(chain []string) []string {
+	o.healthMu.Lock()
+	defer o.healthMu.Unlock()
+
+	healthy := make([]string, 0, len(chain))
+	var unhealthy []string
+	for _, name := range chain {
+		if failedAt, ok := o.unhealthy[name]; ok && time.Since(failedAt) < unhealthyProviderCooldown {
+			unhealthy = append(unhealthy, name)
+			continue
+		}
+		healthy = append(healthy, name)
+	}
+	return append(healthy, unhealthy...)
+}
+
+// acquireBrowserSession takes a session from the first provider in the chain
+// that can create one, and returns the provider used. The session is nil when
+// the chain falls back to Mix's local browser.
+func (o *Orchestrator) acquireBrowserSession(ctx context.Context, chain []string) (*providers.BrowserSession, string, error) {
+	logger := loggerFrom(ctx)
+	var failures []error
+
+	for _, name := range o.orderBrowserProviders(chain) {
+		if name == LocalBrowser {
+			return nil, LocalBrowser, nil
+		}
+
+		session, err := o.acquirePooledSession(ctx, name)
+		if err == nil {
+			return session, name, nil
+		}
+		if ctx.Err() != nil {
+			return nil, "", err
+		}
+		failures = append(failures, fmt.Errorf("%s: %w", name, err))
+
+		// A failed health check keeps the provider at the back of the chain
+		// for the next tasks; a passing one means the failure was transient
+		healthErr := o.checkBrowserProvider(ctx, name)
+		logger.Warn("browser provider failed, trying next", "provider", name, "error", err, "health_check_error", healthErr)
+	}
+
+	return nil, "", errors.Join(failures...)
+}
+
+// browserPool returns the session pool for the named provider, creating an
+// unwarmed one on first use
+func (o *Orchestrator) browserPool(name string) (*providers.Pool, error) {
+	o.poolsMu.Lock()
+	defer o.poolsMu.Unlock()
+	return o.browserPoolLocked(name, providers.PoolOptions{})
+}
+
+func (o *Orchestrator) browserPoolLocked(name string, opts providers.PoolOptions) (*providers.Pool, error) {
+	if pool, ok := o.pools[name]; ok {
+		return pool, nil
+	}
+	provider, err := o.browsers.Get(providers.BrowserProvider(name))
+	if err != nil {
+		return nil, err
+	}
+	opts.MaxSessions = o.config.BrowserSessionLimit
+	pool := providers.NewPool(provider, opts)
+	if o.pools == nil {
+		o.pools = make(map[string]*providers.Pool)
+	}
+	o.pools[name] = pool
+	return pool, nil
+}
+
+// warmBrowserPools pre-creates a session per parallel slot for the provider
+// each task is expected to use, so tasks do not wait for session creation
+func (o *Orchestrator) warmBrowserPools(ctx context.Context, tasks []convex.Task, parallelism int) {
+	demand := make(map[string]int)
+	for _, task := range tasks {
+		chain := o.orderBrowserProviders(o.taskBrowserProviders(task))
+		if len(chain) > 0 && chain[0] != LocalBrowser {
+			demand[chain[0]]++
+		}
+	}
+
+	var wg sync.WaitGroup
+	for name, count := range demand {
+		o.poolsMu.Lock()
+		pool, err := o.browserPoolLocked(name, providers.PoolOptions{
+			Size:   min(parallelism, count),
+			Demand: count,
+		})
+		o.poolsMu.Unlock()
+		if err != nil {
+			// Reported by each task that needs the provider
+			continue
+		}
+
+		wg.Add(1)
+		go func(name string, count int) {
+			defer wg.Done()
+			start := time.Now()
+			if err := pool.Warm(ctx); err != nil {
+				o.logger.Warn("failed to warm browser sessions", "provider", name, "error", err)
+				return
+			}
+			o.logger.Info("browser sessions warmed", "provider", name,
+				"sessions", min(parallelism, count), "duration", time.Since(start).Round(time.Millisecond))
+		}(name, count)
+	}
+	wg.Wait()
+}
+
+// closeBrowserPools closes the idle sessions of every pool
+func (o *Orchestrator) closeBrowserPools(ctx context.Context) {
+	o.poolsMu.Lock()
+	pools := o.pools
+	o.pools = nil
+	o.poolsMu.Unlock()
+
+	for name, pool := range pools {
+		if err := pool.Close(ctx); err != nil {
+			o.logger.Warn("failed to close browser sessions", "provider", name, "error", err)
+		}
+	}
+}
+
+// acquirePooledSession takes a session from the named provider's pool
+func (o *Orchestrator) acquirePooledSession(ctx context.Context, name string) (*providers.BrowserSession, error) {
+	pool, err := o.browserPool(name)
+	if err != nil {
+		return nil, err
+	}
+	return pool.Acquire(ctx)
+}
+
+// releaseBrowserSession hands a browser session back to its provider's pool
+func (o *Orchestrator) releaseBrowserSession(ctx context.Context, session *providers.BrowserSession, reusable bool) {
+	pool, err := o.browserPool(string(session.Provider))
+	if err == nil {
+		err = pool.Release(ctx, session, reusable)
+	}
+	if err != nil {
+		loggerFrom(ctx).Warn("failed to close browser session",
+			"provider", session.Provider, "browser_session_id", session.SessionID, "error", err)
+	}
+}
@@ -0,0 +1,104 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"mix-eval-go/pkg/convex"
+	"mix-eval-go/pkg/providers"
+)
+
+// stubProvider creates sessions unless createErr is set
+type stubProvider struct {
+	name      providers.BrowserProvider
+	createErr error
+	healthErr error
+	creates   int
+}
+
+func (s *stubProvider) Name() providers.BrowserProvider { return s.name }
+
+func (s *stubProvider) Create(ctx context.Context) (*providers.BrowserSession, error) {
+	s.creates++
+	if s.createErr != nil {
+		return nil, s.createErr
+	}
+	return &providers.BrowserSession{CDPURL: "wss://" + string(s.name), Provider: s.name}, nil
+}
+
+func (s *stubProvider) Close(ctx context.Context, session *providers.BrowserSession) error {
+	return nil
+}
+
+func (s *stubProvider) HealthCheck(ctx context.Context) error { return s.healthErr }
+
+func TestParseBrowserProviders(t *testing.T) {
+	got := ParseBrowserProviders(" browserbase, hyperbrowser,,local ")
+	want := []string{"browserbase", "hyperbrowser", "local"}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestBrowserProviderFailover(t *testing.T) {
+	down := &stubProvider{name: "browserbase", createErr: errors.New("status 503"), healthErr: errors.New("status 503")}
+	up := &stubProvider{name: "hyperbrowser"}
+	o := &Orchestrator{
+		browsers: providers.NewRegistry(down, up),
+		logger:   slog.Default(),
+		config:   Config{BrowserProvider: "browserbase,hyperbrowser"},
+	}
+	ctx := context.Background()
+	chain := o.taskBrowserProviders(convex.Task{})
+
+	session, provider, err := o.acquireBrowserSession(ctx, chain)
+	if err != nil {
+		t.Fatalf("Expected failover to hyperbrowser, got %v", err)
+	}
+	if provider != "hyperbrowser" || session.Provider != "hyperbrowser" {
+		t.Errorf("Expected a hyperbrowser session, got %s", provider)
+	}
+
+	// The failed health check moves browserbase to the back of the chain
+	if _, provider, _ = o.acquireBrowserSession(ctx, chain); provider != "hyperbrowser" || down.creates != 1 {
+		t.Errorf("Expected the unhealthy provider to be skipped (creates=%d)", down.creates)
+	}
+
+	// Mix's local browser ends the chain without a session
+	up.createErr = errors.New("status 402")
+	session, provider, err = o.acquireBrowserSession(ctx, []string{"hyperbrowser", LocalBrowser})
+	if err != nil || session != nil || provider != LocalBrowser {
+		t.Errorf("Expected the local browser fallback, got %v, %s, %v", session, provider, err)
+	}
+
+	if _, _, err := o.acquireBrowserSession(ctx, chain); err == nil {
+		t.Error("Expected an error when every provider fails")
+	}
+}
+
+func TestCheckBrowserProviders(t *testing.T) {
+	down := &stubProvider{name: "browserbase", healthErr: errors.New("credentials rejected")}
+	up := &stubProvider{name: "hyperbrowser"}
+	o := &Orchestrator{browsers: providers.NewRegistry(down, up), logger: slog.Default()}
+	ctx := context.Background()
+
+	if err := o.CheckBrowserProviders(ctx, "browserbase,hyperbrowser"); err != nil {
+		t.Errorf("Expected the chain to fall back to hyperbrowser, got %v", err)
+	}
+	if err := o.CheckBrowserProviders(ctx, "browserbase"); err == nil {
+		t.Error("Expected an error when no provider is healthy")
+	}
+	if err := o.CheckBrowserProviders(ctx, "browserbase,local"); err != nil {
+		t.Errorf("Expected the local browser to be usable, got %v", err)
+	}
+	if err := o.CheckBrowserProviders(ctx, "hyperbrowser,nope"); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+}
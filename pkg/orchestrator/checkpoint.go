@@ -0,0 +1,134 @@
+package orchestrator
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// checkpointFileName is the ledger file inside each run directory
+const checkpointFileName = "checkpoint.jsonl"
+
+// TaskStatus is a task's state in the checkpoint ledger
+type TaskStatus string
+
+const (
+	TaskStatusPending   TaskStatus = ""
+	TaskStatusInFlight  TaskStatus = "in_flight"
+	TaskStatusCompleted TaskStatus = "completed"
+	// TaskStatusErrored is a task saved with an infra error after its retries
+	TaskStatusErrored TaskStatus = "errored"
+	// TaskStatusFailed is a task that ended without a saved result
+	TaskStatusFailed TaskStatus = "failed"
+)
+
+// checkpointEntry is a single state transition recorded in the ledger
+type checkpointEntry struct {
+	RunID     string     `json:"runId"`
+	TaskID    string     `json:"taskId"`
+	Status    TaskStatus `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// Checkpoint is an append-only ledger of task states for one run, stored as
+// <outputDir>/<runID>/checkpoint.jsonl. The last entry for a task wins, so a
+// crash mid-write loses at most the latest transition.
+type Checkpoint struct {
+	runID  string
+	path   string
+	mu     sync.Mutex
+	states map[string]TaskStatus
+}
+
+// OpenCheckpoint loads (or creates) the checkpoint ledger for a run
+func OpenCheckpoint(outputDir, runID string) (*Checkpoint, error) {
+	dir := runDir(outputDir, runID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create run dir failed: %w", err)
+	}
+
+	c := &Checkpoint{
+		runID:  runID,
+		path:   filepath.Join(dir, checkpointFileName),
+		states: make(map[string]TaskStatus),
+	}
+
+	f, err := os.Open(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open checkpoint failed: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry checkpointEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// A torn final line from a crash is expected; skip it
+			continue
+		}
+		c.states[entry.TaskID] = entry.Status
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read checkpoint failed: %w", err)
+	}
+
+	return c, nil
+}
+
+// Status returns the last recorded status of a task
+func (c *Checkpoint) Status(task convex.Task) TaskStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.states[checkpointKey(task)]
+}
+
+// Mark records a task's new status, with the error that caused a failure
+func (c *Checkpoint) Mark(task convex.Task, status TaskStatus, cause error) error {
+	entry := checkpointEntry{
+		RunID:     c.runID,
+		TaskID:    checkpointKey(task),
+		Status:    status,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if cause != nil {
+		entry.Error = cause.Error()
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open checkpoint failed: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write checkpoint failed: %w", err)
+	}
+	c.states[entry.TaskID] = status
+	return nil
+}
+
+// checkpointKey identifies a task (and trial) within its run's ledger
+func checkpointKey(task convex.Task) string {
+	if task.Trial > 0 {
+		return fmt.Sprintf("%s#%d", task.ID, task.Trial)
+	}
+	return task.ID
+}
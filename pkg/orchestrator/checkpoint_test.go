@@ -0,0 +1,167 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"mix-eval-go/pkg/convex"
+)
+
+func TestCheckpointResume(t *testing.T) {
+	dir := t.TempDir()
+	tasks := []convex.Task{
+		{ID: "done", RunID: "run-1"},
+		{ID: "crashed", RunID: "run-1"},
+		{ID: "failed", RunID: "run-1"},
+		{ID: "errored", RunID: "run-1"},
+		{ID: "new", RunID: "run-1"},
+	}
+
+	checkpoint, err := OpenCheckpoint(dir, "run-1")
+	if err != nil {
+		t.Fatalf("OpenCheckpoint failed: %v", err)
+	}
+	for _, step := range []struct {
+		task   convex.Task
+		status TaskStatus
+		cause  error
+	}{
+		{tasks[0], TaskStatusInFlight, nil},
+		{tasks[0], TaskStatusCompleted, nil},
+		{tasks[1], TaskStatusInFlight, nil},
+		{tasks[2], TaskStatusInFlight, nil},
+		{tasks[2], TaskStatusFailed, errors.New("task cancelled")},
+		{tasks[3], TaskStatusInFlight, nil},
+		{tasks[3], TaskStatusErrored, errors.New("send message failed")},
+	} {
+		if err := checkpoint.Mark(step.task, step.status, step.cause); err != nil {
+			t.Fatalf("Mark failed: %v", err)
+		}
+	}
+
+	// Reopen from disk, as a resumed process would
+	reopened, err := OpenCheckpoint(dir, "run-1")
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	want := map[string]TaskStatus{
+		"done":    TaskStatusCompleted,
+		"crashed": TaskStatusInFlight,
+		"failed":  TaskStatusFailed,
+		"errored": TaskStatusErrored,
+		"new":     TaskStatusPending,
+	}
+	for _, task := range tasks {
+		if got := reopened.Status(task); got != want[task.ID] {
+			t.Errorf("Task %s: expected status %q, got %q", task.ID, want[task.ID], got)
+		}
+	}
+
+	pending, completed := pendingTasks(slog.Default(), tasks, map[string]*Checkpoint{"run-1": reopened})
+	if len(completed) != 1 || completed[0].ID != "done" {
+		t.Errorf("Expected only the completed task to be skipped, got %v", completed)
+	}
+	if len(pending) != 4 {
+		t.Fatalf("Expected 4 pending tasks, got %d", len(pending))
+	}
+	for _, task := range pending {
+		if task.ID == "done" {
+			t.Errorf("Task %s completed and should be skipped on resume", task.ID)
+		}
+	}
+}
+
+func TestResumeSummarizesWholeRun(t *testing.T) {
+	outputDir := t.TempDir()
+	sink, err := NewLocalSink(outputDir)
+	if err != nil {
+		t.Fatalf("NewLocalSink failed: %v", err)
+	}
+	tasks := expandTrials([]convex.Task{{ID: "task-1", RunID: "run-1"}, {ID: "task-2", RunID: "run-1"}}, 2)
+
+	// An earlier attempt passed task-1 trial 1, failed task-2 trial 1 and hit
+	// an outage on task-1 trial 2; task-2 trial 2 never ran
+	checkpoint, err := OpenCheckpoint(outputDir, "run-1")
+	if err != nil {
+		t.Fatalf("OpenCheckpoint failed: %v", err)
+	}
+	earlier := []struct {
+		task   convex.Task
+		status TaskStatus
+		result *convex.TaskResult
+	}{
+		{tasks[0], TaskStatusCompleted, &convex.TaskResult{Evaluation: &convex.Evaluation{Passed: true, Score: 1}}},
+		{tasks[1], TaskStatusCompleted, &convex.TaskResult{Evaluation: &convex.Evaluation{Score: 0.2}}},
+		{tasks[2], TaskStatusErrored, &convex.TaskResult{InfraError: "send message failed", Evaluation: &convex.Evaluation{Errors: []string{convex.ErrorCategoryInfra}}}},
+	}
+	for _, e := range earlier {
+		e.result.RunID, e.result.TaskID, e.result.Trial = e.task.RunID, e.task.ID, e.task.Trial
+		if err := sink.SaveTaskResult(context.Background(), e.result); err != nil {
+			t.Fatalf("SaveTaskResult failed: %v", err)
+		}
+		if err := checkpoint.Mark(e.task, e.status, nil); err != nil {
+			t.Fatalf("Mark failed: %v", err)
+		}
+	}
+
+	o := &Orchestrator{config: Config{OutputDir: outputDir, Resume: true}, sink: sink, logger: slog.Default()}
+	plan, err := o.openRun(context.Background(), tasks)
+	if err != nil {
+		t.Fatalf("openRun failed: %v", err)
+	}
+	if len(plan.tasks) != 2 {
+		t.Fatalf("Expected the errored and unstarted trials to run, got %v", plan.tasks)
+	}
+	runner := &scriptedRunner{}
+	summary, err := o.runTasks(context.Background(), plan, 2, 2, runner.run)
+	if err != nil {
+		t.Fatalf("runTasks failed: %v", err)
+	}
+
+	if summary.RunID != "run-1" || summary.Total != 4 || summary.Resumed != 2 || summary.Partial {
+		t.Errorf("Expected a summary of the whole run, got run %q total=%d resumed=%d partial=%v",
+			summary.RunID, summary.Total, summary.Resumed, summary.Partial)
+	}
+	if summary.Passed != 3 || summary.Failed != 1 || summary.Errored != 0 {
+		t.Errorf("Expected 3 passed and 1 failed, got %d passed, %d failed, %d errored", summary.Passed, summary.Failed, summary.Errored)
+	}
+	for _, trial := range summary.Trials {
+		if trial.Trials != 2 {
+			t.Errorf("Task %s: expected pass@k over both trials, got %d", trial.TaskID, trial.Trials)
+		}
+	}
+}
+
+func TestResumeSummaryPartial(t *testing.T) {
+	outputDir := t.TempDir()
+	task := convex.Task{ID: "task-1", RunID: "run-1"}
+	checkpoint, err := OpenCheckpoint(outputDir, "run-1")
+	if err != nil {
+		t.Fatalf("OpenCheckpoint failed: %v", err)
+	}
+	if err := checkpoint.Mark(task, TaskStatusCompleted, nil); err != nil {
+		t.Fatalf("Mark failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		sink ResultSink
+	}{
+		{"result missing from the sink", &LocalSink{dir: outputDir}},
+		{"sink cannot read back", &memorySink{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Orchestrator{config: Config{OutputDir: outputDir, Resume: true}, sink: tt.sink, logger: slog.Default()}
+			plan, err := o.openRun(context.Background(), []convex.Task{task})
+			if err != nil {
+				t.Fatalf("openRun failed: %v", err)
+			}
+			if len(plan.tasks) != 0 || len(plan.done) != 0 || !plan.partial {
+				t.Errorf("Expected a partial plan with nothing to run, got %+v", plan)
+			}
+		})
+	}
+}
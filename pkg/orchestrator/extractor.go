@@ -1,7 +1,11 @@
 package orchestrator
 
 import (
+	"encoding/json"
+
 	"github.com/recreate-run/mix-go-sdk/models/components"
+
+	"mix-eval-go/pkg/convex"
 )
 
 // ExecutionHistory represents agent execution trace
@@ -10,8 +14,11 @@ type ExecutionHistory struct {
 	ScreenshotURLs []string
 	FinalResponse  string
 	Reasoning      string
-	TokensUsed     int64
-	Cost           float64
+	Model          string // model that produced the last assistant message
+	InputTokens    int64
+	OutputTokens   int64
+	TokensUsed     int64   // InputTokens + OutputTokens
+	Cost           float64 // USD, as reported by Mix
 }
 
 // ToolCallDetail represents detailed tool execution
@@ -59,8 +66,49 @@ func extractHistory(messages []components.BackendMessage) ExecutionHistory {
 		if msg.Reasoning != nil {
 			history.Reasoning = *msg.Reasoning
 		}
+
+		if msg.Model != nil && *msg.Model != "" {
+			history.Model = *msg.Model
+		}
+
+		if msg.InputTokens != nil {
+			history.InputTokens += *msg.InputTokens
+		}
+		if msg.OutputTokens != nil {
+			history.OutputTokens += *msg.OutputTokens
+		}
+		if msg.Cost != nil {
+			history.Cost += *msg.Cost
+		}
 	}
 
+	history.TokensUsed = history.InputTokens + history.OutputTokens
+	return history
+}
+
+// agentUsage returns the agent's token usage, or nil when Mix reported none
+func (h ExecutionHistory) agentUsage() *convex.TokenUsage {
+	if h.TokensUsed == 0 && h.Cost == 0 {
+		return nil
+	}
+	return &convex.TokenUsage{
+		InputTokens:  h.InputTokens,
+		OutputTokens: h.OutputTokens,
+		CostUSD:      h.Cost,
+	}
+}
+
+// completeHistory returns the Mix messages as generic JSON objects for
+// TaskResult.CompleteHistory
+func completeHistory(messages []components.BackendMessage) []map[string]interface{} {
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return nil
+	}
+	var history []map[string]interface{}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil
+	}
 	return history
 }
 
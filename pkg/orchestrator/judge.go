@@ -3,7 +3,6 @@ package orchestrator
 import (
 	"context"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
@@ -22,7 +21,8 @@ const (
 
 // Judge evaluates task completion using any JudgeLLM provider.
 type Judge struct {
-	llm JudgeLLM
+	llm  JudgeLLM
+	spec JudgeSpec // recorded on every evaluation when known
 }
 
 // NewJudge creates a judge backed by the given JudgeLLM implementation.
@@ -32,11 +32,14 @@ func NewJudge(llm JudgeLLM) *Judge {
 
 // NewJudgeAnthropic is a convenience constructor for the Anthropic-backed judge.
 func NewJudgeAnthropic(apiKey string, model anthropic.Model) *Judge {
-	return NewJudge(NewAnthropicJudgeLLM(apiKey, model))
+	return &Judge{
+		llm:  NewAnthropicJudgeLLM(apiKey, model),
+		spec: JudgeSpec{Provider: JudgeProviderAnthropic, Model: string(model)},
+	}
 }
 
 // mustJudge panics if err is non-nil; used for judge construction at startup.
-func mustJudge(j *Judge, err error) *Judge {
+func mustJudge(j Evaluator, err error) Evaluator {
 	if err != nil {
 		panic("judge creation failed: " + err.Error())
 	}
@@ -49,10 +52,11 @@ func NewJudgeGemini(apiKey, model string) (*Judge, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewJudge(llm), nil
+	return &Judge{llm: llm, spec: JudgeSpec{Provider: JudgeProviderGemini, Model: model}}, nil
 }
 
-// Evaluate evaluates task completion with multi-turn conversation and inspect_step tool.
+// Evaluate evaluates task completion with multi-turn conversation and
+// inspect_step tool, recording the judge's provider and model on the verdict.
 func (j *Judge) Evaluate(
 	ctx context.Context,
 	task convex.Task,
@@ -63,6 +67,27 @@ func (j *Judge) Evaluate(
 	screenshotPaths []string,
 	screenshotsB64 []string,
 ) (*convex.Evaluation, error) {
+	evaluation, err := j.evaluate(ctx, task, toolCalls, sandboxFiles, finalResponse, intermediateReasoning, screenshotPaths, screenshotsB64)
+	if err != nil {
+		return nil, err
+	}
+	evaluation.JudgeProvider = j.spec.Provider
+	evaluation.JudgeModel = j.spec.Model
+	return evaluation, nil
+}
+
+func (j *Judge) evaluate(
+	ctx context.Context,
+	task convex.Task,
+	toolCalls []ToolCall,
+	sandboxFiles []SandboxFile,
+	finalResponse string,
+	intermediateReasoning []string,
+	screenshotPaths []string,
+	screenshotsB64 []string,
+) (*convex.Evaluation, error) {
+	logger := loggerFrom(ctx)
+
 	// Format inputs
 	taskText := truncate(task.Text, maxTask)
 
@@ -123,9 +148,9 @@ func (j *Judge) Evaluate(
 	// Collect and limit screenshots
 	imageURLs := collectImageURLs(screenshotPaths, screenshotsB64)
 	if len(imageURLs) == 0 {
-		log.Printf("Warning: no screenshots available for judge evaluation - verdict will rely solely on tool call history and final response")
+		logger.Warn("no screenshots available for judge evaluation - verdict will rely solely on tool call history and final response")
 	} else if len(imageURLs) > maxImages {
-		log.Printf("Limiting screenshots from %d to %d", len(imageURLs), maxImages)
+		logger.Debug("limiting judge screenshots", "available", len(imageURLs), "max", maxImages)
 		imageURLs = imageURLs[len(imageURLs)-maxImages:]
 	}
 
@@ -175,7 +200,7 @@ func (j *Judge) Evaluate(
 			// No JSON found
 			jsonRetryCount++
 			if jsonRetryCount >= maxJSONRetries {
-				log.Printf("Judge failed to produce valid JSON after %d retries", maxJSONRetries)
+				logger.Warn("judge failed to produce valid JSON", "retries", maxJSONRetries)
 				return &convex.Evaluation{
 					Passed:         false,
 					Score:          0.0,
@@ -186,7 +211,7 @@ func (j *Judge) Evaluate(
 				}, nil
 			}
 
-			log.Printf("No JSON in judge response (retry %d/%d), retrying", jsonRetryCount, maxJSONRetries)
+			logger.Debug("no JSON in judge response, retrying", "retry", jsonRetryCount, "max_retries", maxJSONRetries)
 			messages = append(messages, JudgeMessage{Role: "assistant", Content: responseText})
 			messages = append(messages, JudgeMessage{Role: "user", Content: "Please respond with a single valid JSON object only. Either an inspect_step call or your final verdict."})
 			continue
@@ -211,7 +236,7 @@ func (j *Judge) Evaluate(
 		if inspectObj != nil && inspectCount < maxInspectCalls {
 			result = inspectObj
 			if verdictObj != nil {
-				log.Println("Judge returned both inspect_step and verdict; processing inspect_step first")
+				logger.Debug("judge returned both inspect_step and verdict; processing inspect_step first")
 			}
 		} else if verdictObj != nil {
 			result = verdictObj
@@ -225,7 +250,7 @@ func (j *Judge) Evaluate(
 			stepIdx, _ := result["step_index"].(float64) // JSON numbers are float64
 			query, _ := result["query"].(string)
 
-			log.Printf("Judge inspecting step %d: %s", int(stepIdx), truncate(query, 100))
+			logger.Debug("judge inspecting step", "step", int(stepIdx), "query", truncate(query, 100))
 			inspectResult, err := inspectStep(
 				ctx,
 				int(stepIdx),
@@ -266,7 +291,7 @@ You have %d inspect_step calls remaining. You can inspect more steps or provide
 
 			// Enforcement: verdict=false requires inspect_step
 			if !verdict && inspectCount == 0 {
-				log.Println("Judge attempted verdict=false without using inspect_step - forcing verification")
+				logger.Debug("judge attempted verdict=false without using inspect_step - forcing verification")
 				messages = append(messages, JudgeMessage{Role: "assistant", Content: responseText})
 				messages = append(messages, JudgeMessage{Role: "user", Content: fmt.Sprintf(`**REJECTED: You cannot return verdict=false without first using inspect_step.**
 
@@ -47,6 +47,7 @@ func (a *AnthropicJudgeLLM) Send(ctx context.Context, messages []JudgeMessage) (
 	if err != nil {
 		return "", fmt.Errorf("anthropic judge call failed: %w", err)
 	}
+	recordUsage(ctx, string(a.model), msg.Usage.InputTokens, msg.Usage.OutputTokens)
 
 	var sb strings.Builder
 	for _, block := range msg.Content {
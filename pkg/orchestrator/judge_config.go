@@ -0,0 +1,169 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+)
+
+// Judge providers
+const (
+	JudgeProviderGemini    = "gemini"
+	JudgeProviderAnthropic = "anthropic"
+	JudgeProviderOpenAI    = "openai"
+	// JudgeProviderOpenAICompatible is a self-hosted server speaking the
+	// OpenAI chat completions API at Config.JudgeBaseURL
+	JudgeProviderOpenAICompatible = "openai-compatible"
+	// JudgeProviderEnsemble is recorded on verdicts of an EnsembleJudge
+	JudgeProviderEnsemble = "ensemble"
+)
+
+// defaultJudge is the judge used when none is configured
+var defaultJudge = JudgeSpec{Provider: JudgeProviderGemini, Model: ModelGemini3Flash}
+
+// JudgeSpec names a judge provider and model, written provider:model
+type JudgeSpec struct {
+	Provider string
+	Model    string
+}
+
+func (s JudgeSpec) String() string {
+	return s.Provider + ":" + s.Model
+}
+
+// ParseJudgeSpec parses provider:model; the model defaults to the provider's
+// standard judge model (Gemini 3 Flash, Claude Sonnet 4.5, GPT-4.1)
+func ParseJudgeSpec(spec string) (JudgeSpec, error) {
+	provider, model, _ := strings.Cut(strings.TrimSpace(spec), ":")
+	s := JudgeSpec{Provider: strings.ToLower(strings.TrimSpace(provider)), Model: strings.TrimSpace(model)}
+	switch s.Provider {
+	case JudgeProviderGemini:
+		if s.Model == "" {
+			s.Model = ModelGemini3Flash
+		}
+	case JudgeProviderAnthropic:
+		if s.Model == "" {
+			s.Model = string(ModelClaude45Sonnet)
+		}
+	case JudgeProviderOpenAI:
+		if s.Model == "" {
+			s.Model = ModelGPT41
+		}
+	case JudgeProviderOpenAICompatible:
+		if s.Model == "" {
+			return JudgeSpec{}, fmt.Errorf("judge %q needs a model served by the openai-compatible server", spec)
+		}
+	default:
+		return JudgeSpec{}, fmt.Errorf("unknown judge provider %q in %q (gemini, anthropic, openai, openai-compatible)", s.Provider, spec)
+	}
+	return s, nil
+}
+
+// ParseJudgeSpecs parses a comma-separated list of provider:model specs
+func ParseJudgeSpecs(specs string) ([]JudgeSpec, error) {
+	var parsed []JudgeSpec
+	for _, spec := range strings.Split(specs, ",") {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		s, err := ParseJudgeSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, s)
+	}
+	return parsed, nil
+}
+
+// checkKey reports a missing API key for the spec's provider
+func (s JudgeSpec) checkKey(config Config) error {
+	switch {
+	case s.Provider == JudgeProviderGemini && config.GeminiAPIKey == "":
+		return fmt.Errorf("judge %s requires GEMINI_API_KEY", s)
+	case s.Provider == JudgeProviderAnthropic && config.AnthropicAPIKey == "":
+		return fmt.Errorf("judge %s requires ANTHROPIC_API_KEY", s)
+	case s.Provider == JudgeProviderOpenAI && config.OpenAIAPIKey == "":
+		return fmt.Errorf("judge %s requires OPENAI_API_KEY", s)
+	case s.Provider == JudgeProviderOpenAICompatible && config.JudgeBaseURL == "":
+		return fmt.Errorf("judge %s requires a base URL (JUDGE_BASE_URL)", s)
+	}
+	return nil
+}
+
+// newSpecJudge creates the judge for a spec
+func newSpecJudge(spec JudgeSpec, config Config) (*Judge, error) {
+	if err := spec.checkKey(config); err != nil {
+		return nil, err
+	}
+	var llm JudgeLLM
+	switch spec.Provider {
+	case JudgeProviderAnthropic:
+		llm = NewAnthropicJudgeLLM(config.AnthropicAPIKey, anthropic.Model(spec.Model))
+	case JudgeProviderOpenAI:
+		llm = NewOpenAIJudgeLLM(config.OpenAIAPIKey, spec.Model)
+	case JudgeProviderOpenAICompatible:
+		llm = NewOpenAICompatibleJudgeLLM(OpenAIJudgeOptions{
+			Model:   spec.Model,
+			APIKey:  config.JudgeAPIKey,
+			BaseURL: config.JudgeBaseURL,
+			Headers: config.JudgeHeaders,
+		})
+	default:
+		var err error
+		if llm, err = NewGeminiJudgeLLM(config.GeminiAPIKey, spec.Model); err != nil {
+			return nil, err
+		}
+	}
+	return &Judge{llm: llm, spec: spec}, nil
+}
+
+// judgeSpec returns the configured single judge
+func (c Config) judgeSpec() JudgeSpec {
+	if c.Judge.Provider == "" {
+		return defaultJudge
+	}
+	return c.Judge
+}
+
+// ValidateJudges checks that the configured judges can be built
+func ValidateJudges(config Config) error {
+	if config.JudgeLLM != nil {
+		return nil
+	}
+	if len(config.JudgeEnsemble) == 0 {
+		return config.judgeSpec().checkKey(config)
+	}
+	if _, err := ParseVoteRule(string(config.JudgeVote)); err != nil {
+		return err
+	}
+	var failures []error
+	for _, spec := range config.JudgeEnsemble {
+		if err := spec.checkKey(config); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// newEvaluator builds the configured judge: an override JudgeLLM, an
+// ensemble, or the single judge of Config.Judge
+func newEvaluator(config Config) (Evaluator, error) {
+	if config.JudgeLLM != nil {
+		return NewJudge(config.JudgeLLM), nil
+	}
+	if len(config.JudgeEnsemble) == 0 {
+		return newSpecJudge(config.judgeSpec(), config)
+	}
+
+	members := make([]EnsembleMember, len(config.JudgeEnsemble))
+	for i, spec := range config.JudgeEnsemble {
+		judge, err := newSpecJudge(spec, config)
+		if err != nil {
+			return nil, err
+		}
+		members[i] = EnsembleMember{Name: spec.String(), Judge: judge}
+	}
+	return NewEnsembleJudge(config.JudgeVote, members...)
+}
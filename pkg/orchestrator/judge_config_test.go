@@ -0,0 +1,92 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mix-eval-go/pkg/convex"
+)
+
+func TestParseJudgeSpecs(t *testing.T) {
+	specs, err := ParseJudgeSpecs("gemini:gemini-3-pro-preview, Anthropic, gemini, openai, openai:qwen2.5-vl:7b")
+	if err != nil {
+		t.Fatalf("ParseJudgeSpecs failed: %v", err)
+	}
+	want := []JudgeSpec{
+		{Provider: JudgeProviderGemini, Model: ModelGemini3Pro},
+		{Provider: JudgeProviderAnthropic, Model: string(ModelClaude45Sonnet)},
+		{Provider: JudgeProviderGemini, Model: ModelGemini3Flash},
+		{Provider: JudgeProviderOpenAI, Model: ModelGPT41},
+		{Provider: JudgeProviderOpenAI, Model: "qwen2.5-vl:7b"},
+	}
+	if len(specs) != len(want) {
+		t.Fatalf("Expected %d specs, got %v", len(want), specs)
+	}
+	for i := range want {
+		if specs[i] != want[i] {
+			t.Errorf("Spec %d: expected %s, got %s", i, want[i], specs[i])
+		}
+	}
+
+	if _, err := ParseJudgeSpecs("gemini,mystery:model"); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+}
+
+func TestValidateJudges(t *testing.T) {
+	ensemble := []JudgeSpec{{Provider: JudgeProviderGemini, Model: ModelGemini3Pro}, {Provider: JudgeProviderAnthropic, Model: string(ModelClaude45Sonnet)}}
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{"default judge", Config{GeminiAPIKey: "g"}, ""},
+		{"default judge without key", Config{}, "GEMINI_API_KEY"},
+		{"openai judge", Config{Judge: JudgeSpec{Provider: JudgeProviderOpenAI, Model: ModelGPT41}, OpenAIAPIKey: "o"}, ""},
+		{"openai judge without key", Config{Judge: JudgeSpec{Provider: JudgeProviderOpenAI, Model: ModelGPT41}, GeminiAPIKey: "g"}, "OPENAI_API_KEY"},
+		{"self-hosted judge", Config{Judge: JudgeSpec{Provider: JudgeProviderOpenAICompatible, Model: "qwen2.5-vl:7b"}, JudgeBaseURL: "http://localhost:11434/v1"}, ""},
+		{"self-hosted judge without server", Config{Judge: JudgeSpec{Provider: JudgeProviderOpenAICompatible, Model: "qwen2.5-vl:7b"}}, "JUDGE_BASE_URL"},
+		{"ensemble", Config{GeminiAPIKey: "g", AnthropicAPIKey: "a", JudgeEnsemble: ensemble}, ""},
+		{"ensemble without anthropic key", Config{GeminiAPIKey: "g", JudgeEnsemble: ensemble}, "ANTHROPIC_API_KEY"},
+		{"override", Config{JudgeLLM: &stubJudgeLLM{}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateJudges(tt.config)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected an error mentioning %s, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestJudgeRecordsIdentity(t *testing.T) {
+	spec := JudgeSpec{Provider: JudgeProviderOpenAI, Model: ModelGPT41}
+	judge := &Judge{llm: &stubJudgeLLM{}, spec: spec}
+	ev, err := judge.Evaluate(context.Background(), convex.Task{Text: "Find the title"}, nil, nil, "Example Domain", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if ev.JudgeProvider != JudgeProviderOpenAI || ev.JudgeModel != ModelGPT41 {
+		t.Errorf("Expected judge %s on the evaluation, got %s:%s", spec, ev.JudgeProvider, ev.JudgeModel)
+	}
+
+	ensemble, err := NewEnsembleJudge(VoteMajority,
+		EnsembleMember{Name: spec.String(), Judge: judge},
+		EnsembleMember{Name: "gemini:" + ModelGemini3Flash, Judge: vote(true)})
+	if err != nil {
+		t.Fatalf("NewEnsembleJudge failed: %v", err)
+	}
+	ev, err = ensemble.Evaluate(context.Background(), convex.Task{}, nil, nil, "", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if want := "openai:gpt-4.1,gemini:" + ModelGemini3Flash; ev.JudgeProvider != JudgeProviderEnsemble || ev.JudgeModel != want {
+		t.Errorf("Expected ensemble judge %s, got %s:%s", want, ev.JudgeProvider, ev.JudgeModel)
+	}
+}
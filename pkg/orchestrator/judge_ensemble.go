@@ -0,0 +1,221 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// Evaluator judges an agent execution; Judge and EnsembleJudge implement it
+type Evaluator interface {
+	Evaluate(
+		ctx context.Context,
+		task convex.Task,
+		toolCalls []ToolCall,
+		sandboxFiles []SandboxFile,
+		finalResponse string,
+		intermediateReasoning []string,
+		screenshotPaths []string,
+		screenshotsB64 []string,
+	) (*convex.Evaluation, error)
+}
+
+// VoteRule combines the verdicts of an ensemble
+type VoteRule string
+
+const (
+	// VoteMajority passes a task when more than half of the judges pass it
+	VoteMajority VoteRule = "majority"
+	// VoteUnanimous passes a task only when every judge passes it
+	VoteUnanimous VoteRule = "unanimous"
+)
+
+// ParseVoteRule parses a vote rule; "" means majority
+func ParseVoteRule(rule string) (VoteRule, error) {
+	switch VoteRule(strings.ToLower(strings.TrimSpace(rule))) {
+	case "", VoteMajority:
+		return VoteMajority, nil
+	case VoteUnanimous:
+		return VoteUnanimous, nil
+	default:
+		return "", fmt.Errorf("unknown vote rule %q (majority, unanimous)", rule)
+	}
+}
+
+// EnsembleMember is one judge of an ensemble
+type EnsembleMember struct {
+	Name  string // e.g. gemini:gemini-3-pro-preview
+	Judge Evaluator
+}
+
+// EnsembleJudge runs several judges on the same execution and combines their
+// verdicts. Tasks the judges disagree on are flagged for human review.
+type EnsembleJudge struct {
+	members []EnsembleMember
+	vote    VoteRule
+}
+
+// NewEnsembleJudge creates an ensemble of at least two judges
+func NewEnsembleJudge(vote VoteRule, members ...EnsembleMember) (*EnsembleJudge, error) {
+	if len(members) < 2 {
+		return nil, fmt.Errorf("judge ensemble needs at least 2 judges, got %d", len(members))
+	}
+	vote, err := ParseVoteRule(string(vote))
+	if err != nil {
+		return nil, err
+	}
+	return &EnsembleJudge{members: members, vote: vote}, nil
+}
+
+// memberVerdict is one ensemble member's evaluation, or why it has none
+type memberVerdict struct {
+	name       string
+	evaluation *convex.Evaluation
+	err        error
+}
+
+// Evaluate runs every member's evaluation loop in parallel and combines the
+// verdicts. Members that fail are left out of the vote (and flag the task for
+// review); the ensemble only fails when every member does.
+func (e *EnsembleJudge) Evaluate(
+	ctx context.Context,
+	task convex.Task,
+	toolCalls []ToolCall,
+	sandboxFiles []SandboxFile,
+	finalResponse string,
+	intermediateReasoning []string,
+	screenshotPaths []string,
+	screenshotsB64 []string,
+) (*convex.Evaluation, error) {
+	verdicts := make([]memberVerdict, len(e.members))
+	var wg sync.WaitGroup
+	for i, member := range e.members {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger := loggerFrom(ctx).With("judge", member.Name)
+			evaluation, err := member.Judge.Evaluate(withLogger(ctx, logger), task, toolCalls, sandboxFiles,
+				finalResponse, intermediateReasoning, screenshotPaths, screenshotsB64)
+			if err != nil {
+				logger.Warn("ensemble judge failed", "error", err)
+			}
+			verdicts[i] = memberVerdict{name: member.Name, evaluation: evaluation, err: err}
+		}()
+	}
+	wg.Wait()
+
+	var failures []error
+	for _, v := range verdicts {
+		if v.err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", v.name, v.err))
+		}
+	}
+	if len(failures) == len(verdicts) {
+		return nil, fmt.Errorf("every ensemble judge failed: %w", errors.Join(failures...))
+	}
+
+	evaluation := e.combine(verdicts)
+	loggerFrom(ctx).Debug("ensemble verdict", "passed", evaluation.Passed, "score", evaluation.Score,
+		"needs_human_review", evaluation.NeedsHumanReview)
+	return evaluation, nil
+}
+
+// combine applies the vote rule to the members' verdicts
+func (e *EnsembleJudge) combine(verdicts []memberVerdict) *convex.Evaluation {
+	voters, passes, impossible, captcha := 0, 0, 0, 0
+	for _, v := range verdicts {
+		if v.err != nil {
+			continue
+		}
+		voters++
+		if v.evaluation.Passed {
+			passes++
+		}
+		if v.evaluation.ImpossibleTask {
+			impossible++
+		}
+		if v.evaluation.ReachedCaptcha {
+			captcha++
+		}
+	}
+
+	passed := passes*2 > voters
+	if e.vote == VoteUnanimous {
+		passed = passes == voters
+	}
+	disagreement := passes > 0 && passes < voters
+
+	// The verdict's reasoning and error categories come from the judges that agree with it
+	var reasoning []string
+	var categories []string
+	seen := make(map[string]bool)
+	var comprehensive map[string]interface{}
+	members := make([]map[string]interface{}, 0, len(verdicts))
+	for _, v := range verdicts {
+		if v.err != nil {
+			members = append(members, map[string]interface{}{"judge": v.name, "error": v.err.Error()})
+			continue
+		}
+		ev := v.evaluation
+		members = append(members, map[string]interface{}{
+			"judge":            v.name,
+			"passed":           ev.Passed,
+			"score":            ev.Score,
+			"reasoning":        ev.Reasoning,
+			"error_categories": ev.Errors,
+			"impossible_task":  ev.ImpossibleTask,
+			"reached_captcha":  ev.ReachedCaptcha,
+		})
+		if ev.Passed != passed {
+			continue
+		}
+		reasoning = append(reasoning, fmt.Sprintf("[%s] %s", v.name, ev.Reasoning))
+		for _, category := range ev.Errors {
+			if !seen[category] {
+				seen[category] = true
+				categories = append(categories, category)
+			}
+		}
+		if comprehensive == nil {
+			comprehensive = make(map[string]interface{}, len(ev.ComprehensiveEval)+2)
+			for k, val := range ev.ComprehensiveEval {
+				comprehensive[k] = val
+			}
+		}
+	}
+	if comprehensive == nil {
+		comprehensive = make(map[string]interface{}, 2)
+	}
+
+	needsReview := disagreement || voters < len(verdicts)
+	comprehensive["needs_human_review"] = needsReview
+	comprehensive["ensemble"] = map[string]interface{}{
+		"vote":         string(e.vote),
+		"passed_votes": passes,
+		"total_votes":  voters,
+		"judges":       members,
+	}
+
+	names := make([]string, len(verdicts))
+	for i, v := range verdicts {
+		names[i] = v.name
+	}
+
+	header := fmt.Sprintf("[Ensemble %s vote: %d/%d passed]", e.vote, passes, voters)
+	return &convex.Evaluation{
+		Passed:            passed,
+		Score:             float64(passes) / float64(voters),
+		Reasoning:         header + "\n" + strings.Join(reasoning, "\n"),
+		Errors:            categories,
+		ImpossibleTask:    impossible*2 > voters,
+		ReachedCaptcha:    captcha*2 > voters,
+		NeedsHumanReview:  needsReview,
+		JudgeProvider:     JudgeProviderEnsemble,
+		JudgeModel:        strings.Join(names, ","),
+		ComprehensiveEval: comprehensive,
+	}
+}
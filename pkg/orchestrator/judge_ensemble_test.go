@@ -0,0 +1,105 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// fixedEvaluator returns the same evaluation, or error, for every task
+type fixedEvaluator struct {
+	evaluation *convex.Evaluation
+	err        error
+}
+
+func (f fixedEvaluator) Evaluate(context.Context, convex.Task, []ToolCall, []SandboxFile, string, []string, []string, []string) (*convex.Evaluation, error) {
+	return f.evaluation, f.err
+}
+
+func vote(passed bool) fixedEvaluator {
+	ev := &convex.Evaluation{Passed: passed, Reasoning: "pass", ComprehensiveEval: map[string]interface{}{"passed": passed}}
+	if !passed {
+		ev.Reasoning = "fail"
+		ev.Errors = []string{"task_incomplete"}
+	}
+	return fixedEvaluator{evaluation: ev}
+}
+
+func TestEnsembleJudge(t *testing.T) {
+	failed := fixedEvaluator{err: errors.New("judge API call failed")}
+
+	tests := []struct {
+		name        string
+		rule        VoteRule
+		judges      []Evaluator
+		passed      bool
+		score       float64
+		needsReview bool
+	}{
+		{"majority pass", VoteMajority, []Evaluator{vote(true), vote(true), vote(false)}, true, 2.0 / 3, true},
+		{"majority fail", VoteMajority, []Evaluator{vote(false), vote(true), vote(false)}, false, 1.0 / 3, true},
+		{"majority tie fails", VoteMajority, []Evaluator{vote(true), vote(false)}, false, 0.5, true},
+		{"unanimous fail", VoteUnanimous, []Evaluator{vote(true), vote(true), vote(false)}, false, 2.0 / 3, true},
+		{"unanimous pass", VoteUnanimous, []Evaluator{vote(true), vote(true)}, true, 1, false},
+		{"agreeing fail", VoteMajority, []Evaluator{vote(false), vote(false)}, false, 0, false},
+		{"failed judge left out", VoteUnanimous, []Evaluator{vote(true), vote(true), failed}, true, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			members := make([]EnsembleMember, len(tt.judges))
+			for i, judge := range tt.judges {
+				members[i] = EnsembleMember{Name: string(rune('a' + i)), Judge: judge}
+			}
+			ensemble, err := NewEnsembleJudge(tt.rule, members...)
+			if err != nil {
+				t.Fatalf("NewEnsembleJudge failed: %v", err)
+			}
+
+			ev, err := ensemble.Evaluate(context.Background(), convex.Task{}, nil, nil, "", nil, nil, nil)
+			if err != nil {
+				t.Fatalf("Evaluate failed: %v", err)
+			}
+			if ev.Passed != tt.passed || ev.Score != tt.score || ev.NeedsHumanReview != tt.needsReview {
+				t.Errorf("Expected passed=%v score=%.2f review=%v, got passed=%v score=%.2f review=%v",
+					tt.passed, tt.score, tt.needsReview, ev.Passed, ev.Score, ev.NeedsHumanReview)
+			}
+			if ev.ComprehensiveEval["needs_human_review"] != tt.needsReview {
+				t.Errorf("comprehensive_evaluation missing needs_human_review: %v", ev.ComprehensiveEval)
+			}
+			details, ok := ev.ComprehensiveEval["ensemble"].(map[string]interface{})
+			if !ok {
+				t.Fatalf("comprehensive_evaluation missing ensemble: %v", ev.ComprehensiveEval)
+			}
+			if judges := details["judges"].([]map[string]interface{}); len(judges) != len(tt.judges) {
+				t.Errorf("Expected %d member verdicts, got %d", len(tt.judges), len(judges))
+			}
+			if !tt.passed && !strings.Contains(ev.Reasoning, "fail") {
+				t.Errorf("Failed verdict should carry the failing judges' reasoning: %q", ev.Reasoning)
+			}
+		})
+	}
+}
+
+func TestEnsembleJudgeAllFailed(t *testing.T) {
+	failed := fixedEvaluator{err: errors.New("judge API call failed")}
+	ensemble, err := NewEnsembleJudge(VoteMajority, EnsembleMember{Name: "a", Judge: failed}, EnsembleMember{Name: "b", Judge: failed})
+	if err != nil {
+		t.Fatalf("NewEnsembleJudge failed: %v", err)
+	}
+	if _, err := ensemble.Evaluate(context.Background(), convex.Task{}, nil, nil, "", nil, nil, nil); err == nil {
+		t.Error("Expected an error when every judge fails")
+	}
+}
+
+func TestNewEnsembleJudgeValidation(t *testing.T) {
+	if _, err := NewEnsembleJudge(VoteMajority, EnsembleMember{Name: "a", Judge: vote(true)}); err == nil {
+		t.Error("Expected an error for a single-judge ensemble")
+	}
+	if _, err := NewEnsembleJudge("plurality", EnsembleMember{Judge: vote(true)}, EnsembleMember{Judge: vote(true)}); err == nil {
+		t.Error("Expected an error for an unknown vote rule")
+	}
+}
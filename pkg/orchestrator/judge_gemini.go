@@ -82,6 +82,10 @@ func (g *GeminiJudgeLLM) Send(ctx context.Context, messages []JudgeMessage) (str
 	if err != nil {
 		return "", fmt.Errorf("gemini send failed: %w", err)
 	}
+	if usage := resp.UsageMetadata; usage != nil {
+		// Thinking tokens are billed as output
+		recordUsage(ctx, g.model, int64(usage.PromptTokenCount), int64(usage.CandidatesTokenCount+usage.ThoughtsTokenCount))
+	}
 
 	var sb strings.Builder
 	if len(resp.Candidates) > 0 && resp.Candidates[0].Content != nil {
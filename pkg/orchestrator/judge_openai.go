@@ -0,0 +1,146 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIJudgeOptions configures a judge on the OpenAI chat completions API or
+// an OpenAI-compatible server (vLLM, llama.cpp server, Ollama, LiteLLM proxy)
+type OpenAIJudgeOptions struct {
+	Model string
+	// APIKey is sent as a bearer token; servers without auth can leave it empty
+	APIKey string
+	// BaseURL is the API root the /chat/completions path is appended to
+	// (default https://api.openai.com/v1)
+	BaseURL string
+	// Headers are added to every request, e.g. for a gateway's own auth
+	Headers map[string]string
+	// HTTPClient overrides the client (default: 5m timeout)
+	HTTPClient *http.Client
+}
+
+// OpenAIJudgeLLM implements JudgeLLM using the OpenAI chat completions API.
+type OpenAIJudgeLLM struct {
+	opts   OpenAIJudgeOptions
+	client *http.Client
+}
+
+// NewOpenAIJudgeLLM creates an OpenAI-backed JudgeLLM.
+func NewOpenAIJudgeLLM(apiKey, model string) JudgeLLM {
+	return NewOpenAICompatibleJudgeLLM(OpenAIJudgeOptions{APIKey: apiKey, Model: model})
+}
+
+// NewOpenAICompatibleJudgeLLM creates a JudgeLLM for any server speaking the
+// OpenAI chat completions API.
+func NewOpenAICompatibleJudgeLLM(opts OpenAIJudgeOptions) JudgeLLM {
+	if opts.BaseURL == "" {
+		opts.BaseURL = "https://api.openai.com/v1"
+	}
+	opts.BaseURL = strings.TrimSuffix(opts.BaseURL, "/")
+	client := opts.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Minute}
+	}
+	return &OpenAIJudgeLLM{opts: opts, client: client}
+}
+
+// openAIMessage is a chat message; Content is a string or a list of parts
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type openAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Usage *struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+func (o *OpenAIJudgeLLM) Send(ctx context.Context, messages []JudgeMessage) (string, error) {
+	if len(messages) == 0 {
+		return "", fmt.Errorf("no messages provided")
+	}
+
+	params := make([]openAIMessage, len(messages))
+	for i, m := range messages {
+		if m.Role != "user" || len(m.Images) == 0 {
+			params[i] = openAIMessage{Role: m.Role, Content: m.Content}
+			continue
+		}
+		parts := []openAIContentPart{{Type: "text", Text: m.Content}}
+		for _, img := range m.Images {
+			parts = append(parts, openAIContentPart{
+				Type:     "image_url",
+				ImageURL: &openAIImageURL{URL: "data:" + img.MIMEType + ";base64," + img.B64Data},
+			})
+		}
+		params[i] = openAIMessage{Role: m.Role, Content: parts}
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"model":      o.opts.Model,
+		"max_tokens": 4096,
+		"messages":   params,
+	})
+	if err != nil {
+		return "", fmt.Errorf("openai request encoding failed: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.opts.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("openai request creation failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if o.opts.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.opts.APIKey)
+	}
+	for name, value := range o.opts.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("openai judge call failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("openai judge call failed: status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("openai response decoding failed: %w", err)
+	}
+	if result.Usage != nil {
+		recordUsage(ctx, o.opts.Model, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("openai judge returned no choices")
+	}
+	return result.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,95 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenAICompatibleJudgeLLM(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Expected no Authorization header without an API key, got %q", auth)
+		}
+		if team := r.Header.Get("X-Team"); team != "evals" {
+			t.Errorf("Expected custom header X-Team=evals, got %q", team)
+		}
+
+		var req struct {
+			Model    string `json:"model"`
+			Messages []struct {
+				Role    string          `json:"role"`
+				Content json.RawMessage `json:"content"`
+			} `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Invalid request body: %v", err)
+			return
+		}
+		if req.Model != "qwen2.5-vl:7b" || len(req.Messages) != 2 {
+			t.Errorf("Unexpected request: model %q, %d messages", req.Model, len(req.Messages))
+		}
+
+		var parts []openAIContentPart
+		if err := json.Unmarshal(req.Messages[0].Content, &parts); err != nil {
+			t.Errorf("Expected the user turn as content parts: %v", err)
+			return
+		}
+		if len(parts) != 2 || parts[0].Type != "text" || parts[1].Type != "image_url" {
+			t.Errorf("Unexpected content parts: %+v", parts)
+			return
+		}
+		if url := parts[1].ImageURL.URL; url != "data:image/png;base64,aGVsbG8=" {
+			t.Errorf("Expected a base64 data URL, got %q", url)
+		}
+		var text string
+		if err := json.Unmarshal(req.Messages[1].Content, &text); err != nil || text != "Answer in JSON." {
+			t.Errorf("Expected a text-only turn as a string, got %s", req.Messages[1].Content)
+		}
+
+		w.Write([]byte(`{"choices":[{"message":{"content":"{\"verdict\": true}"}}],"usage":{"prompt_tokens":1200,"completion_tokens":30}}`))
+	}))
+	defer srv.Close()
+
+	llm := NewOpenAICompatibleJudgeLLM(OpenAIJudgeOptions{
+		Model:   "qwen2.5-vl:7b",
+		BaseURL: srv.URL + "/v1/",
+		Headers: map[string]string{"X-Team": "evals"},
+	})
+	meter := &usageMeter{}
+	reply, err := llm.Send(withUsageMeter(context.Background(), meter), []JudgeMessage{
+		{Role: "user", Content: "Judge this run", Images: []JudgeImage{{MIMEType: "image/png", B64Data: "aGVsbG8="}}},
+		{Role: "user", Content: "Answer in JSON."},
+	})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if reply != `{"verdict": true}` {
+		t.Errorf("Unexpected reply: %q", reply)
+	}
+	if usage := meter.total(); usage == nil || usage.InputTokens != 1200 || usage.OutputTokens != 30 || !usage.Unpriced {
+		t.Errorf("Expected an unpriced call to be metered, got %+v", usage)
+	}
+}
+
+func TestOpenAICompatibleJudgeLLMErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret" {
+			t.Errorf("Expected the API key as a bearer token, got %q", auth)
+		}
+		http.Error(w, `{"error":"model not found"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	llm := NewOpenAICompatibleJudgeLLM(OpenAIJudgeOptions{Model: "missing", APIKey: "secret", BaseURL: srv.URL})
+	_, err := llm.Send(context.Background(), []JudgeMessage{{Role: "user", Content: "Judge this run"}})
+	if err == nil || !strings.Contains(err.Error(), "status 404") || !strings.Contains(err.Error(), "model not found") {
+		t.Errorf("Expected the server's error, got %v", err)
+	}
+}
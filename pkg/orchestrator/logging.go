@@ -0,0 +1,153 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// logsDirName holds per-task log files inside each run directory
+const logsDirName = "logs"
+
+// Log formats accepted by NewLogHandler
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// NewLogHandler creates a slog handler writing the given format to w.
+// Lifecycle events are logged at Info; agent stream details (tool calls,
+// thinking, content) at Debug, so an Info level gives a quiet console.
+func NewLogHandler(w io.Writer, format string, level slog.Leveler) (slog.Handler, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch format {
+	case LogFormatText, "":
+		return slog.NewTextHandler(w, opts), nil
+	case LogFormatJSON:
+		return slog.NewJSONHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("unknown log format: %s (want text or json)", format)
+	}
+}
+
+type loggerKey struct{}
+
+// withLogger attaches a task logger to ctx
+func withLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// loggerFrom returns the task logger attached to ctx, or the default logger
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// taskLogger returns a logger tagged with the task's run ID and task ID. When
+// an output directory is configured, records are also written (at every
+// level) to <outputDir>/<runID>/logs/<taskID>.log. The returned func closes
+// the log file.
+func (o *Orchestrator) taskLogger(task convex.Task) (*slog.Logger, func()) {
+	attrs := []any{"run_id", task.RunID, "task_id", task.ID}
+	if task.Trial > 0 {
+		attrs = append(attrs, "trial", task.Trial)
+	}
+
+	handler := o.logger.Handler()
+	closeLog := func() {}
+	if o.config.OutputDir != "" {
+		f, err := openTaskLogFile(o.config.OutputDir, task)
+		if err != nil {
+			o.logger.Warn("per-task log file disabled", append(attrs, "error", err)...)
+		} else {
+			// The format was validated when the console handler was built
+			fileHandler, _ := NewLogHandler(f, o.config.LogFormat, slog.LevelDebug)
+			handler = teeHandler{handler, fileHandler}
+			closeLog = func() { f.Close() }
+		}
+	}
+	return slog.New(handler).With(attrs...), closeLog
+}
+
+// openTaskLogFile opens a task's log file for appending, so retries and
+// resumed runs add to the same file
+func openTaskLogFile(outputDir string, task convex.Task) (*os.File, error) {
+	dir := filepath.Join(runDir(outputDir, task.RunID), logsDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create log dir failed: %w", err)
+	}
+	path := filepath.Join(dir, safePathComponent(checkpointKey(task))+".log")
+	return os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+}
+
+// teeHandler fans each record out to every handler that accepts its level
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
+
+// streamText buffers streamed thinking/content deltas so each block is logged
+// as a single record instead of being interleaved with other tasks' output
+type streamText struct {
+	kind string
+	text strings.Builder
+}
+
+// add appends a delta, flushing the previous block when the kind changes
+func (s *streamText) add(logger *slog.Logger, kind, delta string) {
+	if s.kind != kind {
+		s.flush(logger)
+		s.kind = kind
+	}
+	s.text.WriteString(delta)
+}
+
+// flush logs the buffered block, if any
+func (s *streamText) flush(logger *slog.Logger) {
+	if text := strings.TrimSpace(s.text.String()); text != "" {
+		logger.Debug(s.kind, "text", text)
+	}
+	s.text.Reset()
+	s.kind = ""
+}
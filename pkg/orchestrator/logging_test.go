@@ -0,0 +1,74 @@
+package orchestrator
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mix-eval-go/pkg/convex"
+)
+
+func TestTaskLoggerWritesPerTaskFile(t *testing.T) {
+	dir := t.TempDir()
+	var console bytes.Buffer
+	consoleHandler, err := NewLogHandler(&console, LogFormatText, slog.LevelInfo)
+	if err != nil {
+		t.Fatalf("NewLogHandler failed: %v", err)
+	}
+	o := &Orchestrator{
+		logger: slog.New(consoleHandler),
+		config: Config{OutputDir: dir, LogFormat: LogFormatJSON},
+	}
+
+	task := convex.Task{ID: "task-1", RunID: "run-1", Trial: 2}
+	logger, closeLog := o.taskLogger(task)
+	logger.Info("task started")
+	logger.Debug("tool started", "tool", "browser_navigate")
+	closeLog()
+
+	// Quiet console: lifecycle only
+	if !strings.Contains(console.String(), "task started") || !strings.Contains(console.String(), "task_id=task-1") {
+		t.Errorf("Console missing lifecycle line: %q", console.String())
+	}
+	if strings.Contains(console.String(), "tool started") {
+		t.Errorf("Console should not show debug lines: %q", console.String())
+	}
+
+	// Task file: every level, in the configured format
+	data, err := os.ReadFile(filepath.Join(dir, "run-1", logsDirName, "task-1#2.log"))
+	if err != nil {
+		t.Fatalf("Read task log failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log lines, got %d: %q", len(lines), data)
+	}
+	if !strings.Contains(lines[1], `"msg":"tool started"`) || !strings.Contains(lines[1], `"trial":2`) {
+		t.Errorf("Unexpected task log line: %s", lines[1])
+	}
+}
+
+func TestStreamTextBuffersBlocks(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	var text streamText
+	text.add(logger, "agent thinking", "Let me ")
+	text.add(logger, "agent thinking", "check.")
+	text.add(logger, "agent response", "Done.")
+	text.flush(logger)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 records, got %d: %q", len(lines), out.String())
+	}
+	if !strings.Contains(lines[0], `text="Let me check."`) {
+		t.Errorf("Thinking deltas not joined: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `msg="agent response"`) {
+		t.Errorf("Unexpected second record: %s", lines[1])
+	}
+}
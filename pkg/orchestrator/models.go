@@ -16,3 +16,29 @@ const (
 	ModelGemini3Flash = "gemini-3-flash-preview"
 	ModelGemini3Pro   = "gemini-3-pro-preview"
 )
+
+// OpenAI judge models.
+const (
+	ModelGPT41 = "gpt-4.1"
+)
+
+// ModelPricing is a model's list price in USD per million tokens
+type ModelPricing struct {
+	InputPerMTok  float64
+	OutputPerMTok float64
+}
+
+// judgePricing prices the judge models. Mix reports the agent's cost itself.
+var judgePricing = map[string]ModelPricing{
+	string(ModelClaude45Sonnet): {InputPerMTok: 3, OutputPerMTok: 15},
+	string(ModelClaudeOpus46):   {InputPerMTok: 5, OutputPerMTok: 25},
+	string(ModelClaudeHaiku45):  {InputPerMTok: 1, OutputPerMTok: 5},
+	ModelGemini3Flash:           {InputPerMTok: 0.5, OutputPerMTok: 3},
+	ModelGemini3Pro:             {InputPerMTok: 2, OutputPerMTok: 12},
+	ModelGPT41:                  {InputPerMTok: 2, OutputPerMTok: 8},
+}
+
+// Cost returns the price of a call
+func (p ModelPricing) Cost(inputTokens, outputTokens int64) float64 {
+	return (float64(inputTokens)*p.InputPerMTok + float64(outputTokens)*p.OutputPerMTok) / 1e6
+}
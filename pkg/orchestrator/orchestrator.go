@@ -1,13 +1,15 @@
 package orchestrator
 
 import (
-	"bufio"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -18,32 +20,109 @@ import (
 
 	"mix-eval-go/pkg/convex"
 	"mix-eval-go/pkg/providers"
+	"mix-eval-go/pkg/sse"
 )
 
 // Orchestrator manages the evaluation pipeline
 type Orchestrator struct {
 	mixClient    *mix.Mix
 	convexClient *convex.Client
-	judge        *Judge
+	judge        Evaluator
+	browsers     *providers.Registry
+	pools        map[string]*providers.Pool
+	poolsMu      sync.Mutex
+	unhealthy    map[string]time.Time
+	healthMu     sync.Mutex
+	sink         ResultSink
+	logger       *slog.Logger
 	config       Config
+	models       modelLock
+
+	abortMu sync.Mutex
+	abort   context.CancelFunc
 }
 
 // Config holds orchestrator configuration
 type Config struct {
-	MixURL          string
-	ConvexURL       string
-	ConvexSecretKey string
-	BrowserbaseKey  string
-	BrightdataUser  string
-	BrightdataPass  string
-	GeminiAPIKey string
-}
-
-// ANSI color codes
-const (
-	ANSIColorGray  = "\033[90m"
-	ANSIColorReset = "\033[0m"
-)
+	MixURL               string
+	ConvexURL            string
+	ConvexSecretKey      string
+	BrowserbaseKey       string
+	BrowserbaseProjectID string
+	BrightdataUser       string
+	BrightdataPass       string
+	HyperbrowserKey      string
+	AnchorKey            string
+	GeminiAPIKey         string
+	AnthropicAPIKey      string
+	OpenAIAPIKey         string
+
+	// Judge selects the judge provider and model (default Gemini 3 Flash);
+	// JudgeLLM overrides it with a custom implementation
+	Judge    JudgeSpec
+	JudgeLLM JudgeLLM
+	// JudgeBaseURL, JudgeAPIKey (optional) and JudgeHeaders configure the
+	// server of openai-compatible judges
+	JudgeBaseURL string
+	JudgeAPIKey  string
+	JudgeHeaders map[string]string
+	// JudgeEnsemble judges every task with each of these models and combines
+	// their verdicts with JudgeVote (default majority)
+	JudgeEnsemble []JudgeSpec
+	JudgeVote     VoteRule
+
+	// LocalCDPEndpoints are self-hosted Chrome endpoints (ws:// debugger URLs
+	// or http://host:9222) for the local-cdp provider
+	LocalCDPEndpoints []string
+
+	// BrowserProvider is the comma-separated provider chain for tasks that do
+	// not name their own, tried in order until one creates a session ("local"
+	// is Mix's own browser; "" runs Mix's local browser without failover)
+	BrowserProvider string
+	// BrowserSessionLimit caps the live sessions per browser provider, warm
+	// ones included (0 for no limit)
+	BrowserSessionLimit int
+
+	// Sink receives task results; defaults to the Convex client when nil
+	Sink ResultSink
+
+	// DatasetColumns maps task fields to CSV columns for file datasets
+	DatasetColumns map[string]string
+
+	// OutputDir holds local run artifacts such as the checkpoint ledger
+	OutputDir string
+	// Resume skips tasks the run's checkpoint ledger records as completed
+	Resume bool
+	// Traces writes every raw SSE event and the Mix message history of each
+	// attempt under OutputDir; UploadTraces also stores them in Convex
+	Traces       bool
+	UploadTraces bool
+
+	// TaskTimeout bounds the agent's wall-clock time per task (0 for no limit);
+	// a task's own TimeoutSeconds takes precedence
+	TaskTimeout time.Duration
+	// ShutdownGrace is how long in-flight tasks may finish after a shutdown request
+	ShutdownGrace time.Duration
+
+	// InfraRetries is how many times a task is retried after an infrastructure
+	// failure; RetryBackoff is the initial delay, doubled on each retry
+	InfraRetries int
+	RetryBackoff time.Duration
+
+	// Trials is how many independent times each task runs (default 1)
+	Trials int
+
+	// Model overrides the Mix agent model and MaxSteps caps the agent's tool
+	// steps (0 for no limit); a task's own Model and MaxSteps take precedence.
+	// The model is an account-wide Mix preference, restored when the run ends.
+	Model    string
+	MaxSteps int
+
+	// Logger is the console logger; defaults to slog.Default() when nil.
+	// LogFormat (text or json) is used for per-task log files under OutputDir.
+	Logger    *slog.Logger
+	LogFormat string
+}
 
 // SSEEvent represents a base SSE event structure
 type SSEEvent struct {
@@ -103,46 +182,108 @@ type ErrorEvent struct {
 
 // New creates a new orchestrator instance
 func New(config Config) *Orchestrator {
+	convexClient := convex.NewClient(config.ConvexURL, config.ConvexSecretKey)
+
+	var sink ResultSink = convexClient
+	if config.Sink != nil {
+		sink = config.Sink
+	}
+
+	logger := slog.Default()
+	if config.Logger != nil {
+		logger = config.Logger
+	}
+
 	return &Orchestrator{
 		mixClient:    mix.New(config.MixURL, mix.WithTimeout(30*time.Second)),
-		convexClient: convex.NewClient(config.ConvexURL, config.ConvexSecretKey),
-		judge:        mustJudge(NewJudgeGemini(config.GeminiAPIKey, ModelGemini3Flash)),
+		convexClient: convexClient,
+		judge:        mustJudge(newEvaluator(config)),
+		browsers:     newBrowserRegistry(config),
+		sink:         sink,
+		logger:       logger,
 		config:       config,
 	}
 }
 
-// FetchTasks fetches tasks from Convex
-func (o *Orchestrator) FetchTasks(ctx context.Context, testCaseName string) ([]convex.Task, error) {
-	return o.convexClient.FetchTestCase(ctx, testCaseName)
+// FetchTasks fetches tasks from a local file or, by default, a Convex test case
+func (o *Orchestrator) FetchTasks(ctx context.Context, dataset string) ([]convex.Task, error) {
+	return o.taskSource(dataset).FetchTasks(ctx)
+}
+
+// taskSource picks the task source for a dataset name
+func (o *Orchestrator) taskSource(dataset string) TaskSource {
+	if IsTaskFile(dataset) {
+		return &FileTaskSource{Path: dataset, Columns: o.config.DatasetColumns}
+	}
+	return &ConvexTaskSource{Client: o.convexClient, TestCase: dataset}
 }
 
-// RunTask executes a single evaluation task
+// RunTask executes a single evaluation task, then restores the Mix agent
+// model if the task overrode it
 func (o *Orchestrator) RunTask(ctx context.Context, task convex.Task) (*convex.TaskResult, error) {
+	defer o.restoreModel(context.WithoutCancel(ctx))
+	return o.runTask(ctx, task)
+}
+
+// runTask executes a single evaluation task, leaving the agent model for the
+// next task of the run
+func (o *Orchestrator) runTask(ctx context.Context, task convex.Task) (*convex.TaskResult, error) {
 	// Auto-generate runID if not provided
 	if task.RunID == "" {
 		task.RunID = fmt.Sprintf("run-%d", time.Now().Unix())
-		fmt.Printf("Auto-generated run ID: %s\n", task.RunID)
+		o.logger.Info("auto-generated run ID", "run_id", task.RunID)
+	}
+
+	// Tasks run through RunMultipleTasks already carry their logger
+	if _, ok := ctx.Value(loggerKey{}).(*slog.Logger); !ok {
+		taskLogger, closeLog := o.taskLogger(task)
+		defer closeLog()
+		ctx = withLogger(ctx, taskLogger)
 	}
+	logger := loggerFrom(ctx)
+	logger.Info("task started")
 
-	fmt.Printf("Starting task: %s\n", task.ID)
+	// 1. Select the agent model (a global Mix preference) before taking a
+	// browser, so a task waiting for another model holds no session
+	model := o.taskModel(task)
+	releaseModel, err := o.acquireModel(ctx, model)
+	if err != nil {
+		return nil, infraError("model selection", err)
+	}
+	defer releaseModel()
 
-	// 1. Create browser session if needed
+	// 2. Create browser session if needed
 	var cdpURL string
 	var browserSession *providers.BrowserSession
 
-	if task.BrowserProvider != "" {
-		var err error
-		browserSession, err = o.createBrowserSession(task.BrowserProvider)
+	// The session goes back to its pool once the agent is done with it; it is
+	// only recycled when the agent ran to completion, since a stopped agent
+	// may still be driving the browser
+	browserReleased := false
+	releaseBrowser := func(reusable bool) {
+		if browserSession != nil && !browserReleased {
+			browserReleased = true
+			o.releaseBrowserSession(context.WithoutCancel(ctx), browserSession, reusable)
+		}
+	}
+	defer releaseBrowser(false)
+
+	var browserProvider string
+	if chain := o.taskBrowserProviders(task); len(chain) > 0 {
+		browserSession, browserProvider, err = o.acquireBrowserSession(ctx, chain)
 		if err != nil {
-			return nil, fmt.Errorf("browser session creation failed: %w", err)
+			return nil, infraError("browser session creation", err)
 		}
-		defer o.closeBrowserSession(browserSession)
 
-		cdpURL = browserSession.CDPURL
-		fmt.Printf("Created browser session: %s\n", cdpURL)
+		if browserSession != nil {
+			cdpURL = browserSession.CDPURL
+			logger.Info("browser session created", "provider", browserProvider, "cdp_url", cdpURL)
+		} else {
+			logger.Info("using mix local browser", "provider", browserProvider)
+		}
 	}
 
-	// 2. Create Mix session with CDP URL (if cloud browser was created)
+	// 3. Create the Mix session with CDP URL (if cloud browser was created)
 	browserMode := operations.BrowserModeLocalBrowserService
 	var cdpURLPtr *string
 	if cdpURL != "" {
@@ -151,147 +292,270 @@ func (o *Orchestrator) RunTask(ctx context.Context, task convex.Task) (*convex.T
 	}
 
 	sessionResp, err := o.mixClient.Sessions.CreateSession(ctx, operations.CreateSessionRequest{
-		Title:       fmt.Sprintf("Eval: %s", task.ID),
+		Title:       sessionTitle(task),
 		BrowserMode: browserMode,
 		CdpURL:      cdpURLPtr,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("session creation failed: %w", err)
+		return nil, infraError("session creation", err)
 	}
 
 	sessionID := sessionResp.SessionData.ID
 
-	fmt.Printf("Created Mix session: %s (preserved for file access)\n", sessionID)
+	logger = logger.With("session_id", sessionID)
+	ctx = withLogger(ctx, logger)
+	logger.Info("mix session created", "model", model)
 
-	// 3. Start SSE event stream (manual HTTP, SDK has bug)
-	eventsChan := make(chan map[string]interface{}, 100)
-	var streamWg sync.WaitGroup
-	streamWg.Add(1)
+	// 4. Start SSE event stream (manual HTTP, SDK has bug), bounded by the task timeout
+	agentCtx, cancelAgent := context.WithCancel(ctx)
+	timeout := o.taskTimeout(task)
+	if timeout > 0 {
+		agentCtx, cancelAgent = context.WithTimeout(ctx, timeout)
+	}
+	defer cancelAgent()
 
-	go func() {
-		defer streamWg.Done()
-		o.streamEvents(ctx, sessionID, eventsChan)
-	}()
+	trace := o.openTrace(ctx, task, sessionID)
+	defer trace.close()
 
-	// Wait for stream to connect
-	time.Sleep(1 * time.Second)
+	stream := newEventStream(o.config.MixURL, sessionID, logger)
+	stream.trace = trace
+	go stream.run(agentCtx)
 
-	// 4. Send task message
-	_, err = o.mixClient.Messages.SendMessage(ctx, sessionID, operations.SendMessageRequestBody{
-		Text: task.Text,
-	})
+	// Wait for the stream to connect so no events are missed
+	if err := stream.waitReady(streamConnectTimeout); err != nil {
+		o.cancelSession(context.WithoutCancel(ctx), sessionID)
+		return nil, infraError("event stream", err)
+	}
+
+	// 5. Send task message (Mix enforces the step cap per message)
+	maxSteps := o.taskMaxSteps(task)
+	message := operations.SendMessageRequestBody{Text: task.Text}
+	if maxSteps > 0 {
+		steps := int64(maxSteps)
+		message.MaxSteps = &steps
+	}
+	agentStart := time.Now()
+	_, err = o.mixClient.Messages.SendMessage(ctx, sessionID, message)
 	if err != nil {
-		return nil, fmt.Errorf("send message failed: %w", err)
+		return nil, infraError("send message", err)
 	}
 
-	// 5. Collect events until completion
-	toolCalls, _ := o.collectEvents(eventsChan)
-	streamWg.Wait()
+	// 6. Collect events until completion (or until the task times out). As a
+	// backstop to Mix's own step cap, the session is stopped once the agent
+	// has run maxSteps tools.
+	stepLimitReached := false
+	stopAtStepLimit := func() {
+		stepLimitReached = true
+		logger.Warn("step limit reached, stopping mix session", "max_steps", maxSteps)
+		o.cancelSession(ctx, sessionID)
+		cancelAgent()
+	}
+	toolCalls, _ := o.collectEvents(logger, stream.events, maxSteps, stopAtStepLimit)
+	<-stream.done
+	streamErr := stream.err
+	agentDuration := time.Since(agentStart)
+
+	if ctx.Err() != nil {
+		// The run is being force-stopped: halt the agent and abandon the task
+		o.cancelSession(context.WithoutCancel(ctx), sessionID)
+		return nil, fmt.Errorf("task cancelled: %w", ctx.Err())
+	}
 
-	fmt.Printf("Collected %d tool calls\n", len(toolCalls))
+	if streamErr != nil && agentCtx.Err() == nil {
+		o.cancelSession(ctx, sessionID)
+		return nil, infraError("event stream", streamErr)
+	}
+
+	timedOut := errors.Is(agentCtx.Err(), context.DeadlineExceeded)
+	if timedOut {
+		logger.Warn("task timed out, stopping mix session", "timeout", timeout)
+		o.cancelSession(ctx, sessionID)
+	}
 
-	// 6. Get complete message history
-	messagesResp, err := o.mixClient.Messages.GetSessionMessages(ctx, sessionID)
+	logger.Info("agent finished", "tool_calls", len(toolCalls), "duration", agentDuration.Round(time.Millisecond))
+	releaseBrowser(!timedOut && !stepLimitReached)
+
+	// 7. Get complete message history (retried in place: the execution is kept)
+	var messagesResp *operations.GetSessionMessagesResponse
+	err = o.retryStep(ctx, "get messages", func() error {
+		var err error
+		if messagesResp, err = o.mixClient.Messages.GetSessionMessages(ctx, sessionID); err != nil {
+			return infraError("get messages", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("get messages failed: %w", err)
+		return nil, err
+	}
+
+	if err := trace.writeMessages(messagesResp.BackendMessages); err != nil {
+		logger.Warn("trace incomplete", "error", err)
 	}
 
-	// 7. Extract and format history (includes screenshot URLs from tc.ScreenshotUrls)
+	// 8. Extract and format history (includes screenshot URLs from tc.ScreenshotUrls)
 	history := extractHistory(messagesResp.BackendMessages)
 
-	// 8. Fetch screenshots from message history and convert for judge
-	judgeToolCalls := convertToJudgeToolCalls(history.ToolCalls)
+	// 9. Fetch screenshots from message history for the judge
 	fetchedScreenshots := fetchScreenshots(ctx, o.config.MixURL, history.ScreenshotURLs)
-	screenshotsB64 := convertScreenshotsToBase64(fetchedScreenshots)
-	fmt.Printf("Fetched %d/%d screenshots from message history\n", len(fetchedScreenshots), len(history.ScreenshotURLs))
-	var intermediateReasoning []string
-	if history.Reasoning != "" {
-		intermediateReasoning = []string{history.Reasoning}
+	logger.Info("screenshots fetched", "fetched", len(fetchedScreenshots), "total", len(history.ScreenshotURLs))
+	if err := trace.writeScreenshots(fetchedScreenshots); err != nil {
+		logger.Warn("trace incomplete", "error", err)
 	}
+	traceRef := o.finishTrace(ctx, trace)
 
-	// 9. Judge evaluation
-	evaluation, err := o.judge.Evaluate(
-		ctx,
-		task,
-		judgeToolCalls,
-		[]SandboxFile{}, // No sandbox files from Mix yet
-		history.FinalResponse,
-		intermediateReasoning,
-		nil, // No screenshot file paths
-		screenshotsB64,
-	)
+	// 10. Judge evaluation (retried in place, like the message history)
+	var judged *verdict
+	err = o.retryStep(ctx, "evaluation", func() error {
+		var err error
+		judged, err = o.judgeExecution(ctx, task, convertToJudgeToolCalls(history.ToolCalls),
+			history.FinalResponse, history.Reasoning, fetchedScreenshots)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("evaluation failed: %w", err)
+		return nil, err
+	}
+	evaluation := judged.evaluation
+	if timedOut {
+		markTimedOut(evaluation, timeout)
 	}
 
-	fmt.Printf("Evaluation: Score=%.2f, Passed=%v\n", evaluation.Score, evaluation.Passed)
-
-	// 10. Upload screenshots
-	storageIDs, _ := o.convexClient.UploadScreenshots(ctx, fetchedScreenshots)
+	// 11. Upload screenshots and the message history (kept inline for
+	// offline runs without Convex)
+	var storageIDs []string
+	if o.config.ConvexURL != "" {
+		storageIDs, _ = o.convexClient.UploadScreenshots(ctx, fetchedScreenshots)
+	}
+	historyID, inlineHistory := o.storeHistory(ctx, completeHistory(messagesResp.BackendMessages))
 
-	// 11. Build result
+	// 12. Build result
 	result := &convex.TaskResult{
-		RunID:                task.RunID,
-		TaskID:               task.ID,
-		Task:                 task.Text,
-		ToolCalls:            toolCalls,
-		ScreenshotStorageIDs: storageIDs,
-		FinalResponse:        history.FinalResponse,
-		Evaluation:           evaluation,
+		RunID:                    task.RunID,
+		TaskID:                   task.ID,
+		Trial:                    task.Trial,
+		Task:                     task.Text,
+		ToolCalls:                toolCalls,
+		ScreenshotStorageIDs:     storageIDs,
+		FinalResponse:            history.FinalResponse,
+		Evaluation:               evaluation,
+		CompleteHistory:          inlineHistory,
+		CompleteHistoryStorageID: historyID,
+		Trace:                    traceRef,
+		StreamReconnects:         stream.reconnects,
+		Model:                    model,
+		MaxSteps:                 maxSteps,
+		StepLimitReached:         stepLimitReached,
+		BrowserProvider:          browserProvider,
+		AgentUsage:               history.agentUsage(),
+		JudgeUsage:               judged.usage,
+		TimeToFirstActionMs:      timeToFirstAction(agentStart, toolCalls).Milliseconds(),
+		AgentDurationMs:          agentDuration.Milliseconds(),
+		JudgeDurationMs:          judged.duration.Milliseconds(),
+	}
+	if history.Model != "" {
+		result.Model = history.Model
 	}
 
 	return result, nil
 }
 
-// streamEvents handles SSE event streaming using manual HTTP (SDK has bug)
-func (o *Orchestrator) streamEvents(ctx context.Context, sessionID string, ch chan map[string]interface{}) {
-	defer close(ch)
+// storeHistory uploads a message history to Convex storage and returns its
+// storage ID, or returns the history to save inline when Convex is not
+// configured. A failed upload is logged and the history left out of the result.
+func (o *Orchestrator) storeHistory(ctx context.Context, history []map[string]interface{}) (string, []map[string]interface{}) {
+	if o.config.ConvexURL == "" || len(history) == 0 {
+		return "", history
+	}
+	data, err := json.Marshal(history)
+	if err == nil {
+		var storageID string
+		if storageID, err = o.convexClient.UploadFile(ctx, data, "application/json"); err == nil {
+			return storageID, nil
+		}
+	}
+	loggerFrom(ctx).Warn("message history upload failed, saving the result without it", "error", err)
+	return "", nil
+}
 
-	streamURL := fmt.Sprintf("%s/stream?sessionId=%s", o.config.MixURL, sessionID)
+// verdict is the judge's evaluation of one execution
+type verdict struct {
+	evaluation *convex.Evaluation
+	usage      *convex.TokenUsage
+	duration   time.Duration
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", streamURL, nil)
-	if err != nil {
-		fmt.Printf("Stream request creation failed: %v\n", err)
-		return
+// judgeExecution evaluates an agent execution, metering the tokens of every
+// judge call
+func (o *Orchestrator) judgeExecution(ctx context.Context, task convex.Task, toolCalls []ToolCall, finalResponse, reasoning string, screenshots [][]byte) (*verdict, error) {
+	var intermediateReasoning []string
+	if reasoning != "" {
+		intermediateReasoning = []string{reasoning}
 	}
-	req.Header.Set("Accept", "text/event-stream")
 
-	resp, err := http.DefaultClient.Do(req)
+	usage := &usageMeter{}
+	start := time.Now()
+	evaluation, err := o.judge.Evaluate(
+		withUsageMeter(ctx, usage),
+		task,
+		toolCalls,
+		[]SandboxFile{}, // No sandbox files from Mix yet
+		finalResponse,
+		intermediateReasoning,
+		nil, // No screenshot file paths
+		convertScreenshotsToBase64(screenshots),
+	)
 	if err != nil {
-		fmt.Printf("Stream request failed: %v\n", err)
-		return
+		return nil, infraError("evaluation", err)
 	}
-	defer resp.Body.Close()
+	duration := time.Since(start)
 
-	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Stream returned status %d\n", resp.StatusCode)
-		return
-	}
-
-	scanner := bufio.NewScanner(resp.Body)
-	for scanner.Scan() {
-		line := scanner.Text()
+	loggerFrom(ctx).Info("task evaluated", "score", evaluation.Score, "passed", evaluation.Passed)
+	return &verdict{evaluation: evaluation, usage: usage.total(), duration: duration}, nil
+}
 
-		// SSE format: data: {...}
-		if strings.HasPrefix(line, "data: ") {
-			data := strings.TrimPrefix(line, "data: ")
+// timeToFirstAction is how long the agent took to start its first tool after
+// the task was sent (0 when it started none)
+func timeToFirstAction(sentAt time.Time, toolCalls []convex.ToolCall) time.Duration {
+	var first int64
+	for _, tc := range toolCalls {
+		if tc.StartedAt > 0 && (first == 0 || tc.StartedAt < first) {
+			first = tc.StartedAt
+		}
+	}
+	if first == 0 {
+		return 0
+	}
+	return time.UnixMilli(first).Sub(sentAt)
+}
 
-			var event map[string]interface{}
-			if err := json.Unmarshal([]byte(data), &event); err != nil {
-				continue
-			}
+// sessionTitle names the Mix session for a task (and trial)
+func sessionTitle(task convex.Task) string {
+	if task.Trial > 0 {
+		return fmt.Sprintf("Eval: %s (trial %d)", task.ID, task.Trial)
+	}
+	return fmt.Sprintf("Eval: %s", task.ID)
+}
 
-			ch <- event
+// taskTimeout returns the agent time limit for a task (0 for no limit)
+func (o *Orchestrator) taskTimeout(task convex.Task) time.Duration {
+	if task.TimeoutSeconds > 0 {
+		return time.Duration(task.TimeoutSeconds) * time.Second
+	}
+	return o.config.TaskTimeout
+}
 
-			// Check for completion event
-			if eventType, ok := event["type"].(string); ok && eventType == string(components.SSEEventStreamTypeComplete) {
-				return
-			}
-		}
+// cancelSession tells Mix to stop processing a session's current message
+func (o *Orchestrator) cancelSession(ctx context.Context, sessionID string) {
+	if _, err := o.mixClient.Messages.CancelSessionProcessing(ctx, sessionID); err != nil {
+		loggerFrom(ctx).Warn("failed to cancel mix session", "session_id", sessionID, "error", err)
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Printf("Scanner error: %v\n", err)
+// markTimedOut records the timeout error category on a judged evaluation
+func markTimedOut(evaluation *convex.Evaluation, timeout time.Duration) {
+	evaluation.Errors = append(evaluation.Errors, convex.ErrorCategoryTimeout)
+	evaluation.Reasoning = fmt.Sprintf("[Agent timed out after %s] %s", timeout, evaluation.Reasoning)
+	if evaluation.ComprehensiveEval != nil {
+		evaluation.ComprehensiveEval["error_categories"] = evaluation.Errors
 	}
 }
 
@@ -300,7 +564,8 @@ type ToolCallInfo struct {
 	ID          string
 	Name        string
 	Description string
-	Parameters  string // JSON string representation
+	Parameters  string    // JSON string representation
+	StartedAt   time.Time // when tool_execution_start arrived
 }
 
 // parseEvent parses raw event map into typed event
@@ -345,22 +610,20 @@ func parseEvent(eventData []byte, eventType string) interface{} {
 	return nil
 }
 
-// collectEvents processes SSE events
-func (o *Orchestrator) collectEvents(eventsChan chan map[string]interface{}) ([]convex.ToolCall, [][]byte) {
+// collectEvents processes SSE events, logging agent activity at Debug level.
+// With maxSteps > 0, stop is called once the agent completes its maxSteps-th
+// tool execution; tools it runs after that are not recorded.
+func (o *Orchestrator) collectEvents(logger *slog.Logger, events <-chan sse.Event, maxSteps int, stop func()) ([]convex.ToolCall, [][]byte) {
 	var toolCalls []convex.ToolCall
 	var screenshots [][]byte
 	toolCallsMap := make(map[string]*ToolCallInfo)
-	var thinkingActive bool
-
-	for rawEvent := range eventsChan {
-		eventType, _ := rawEvent["type"].(string)
+	var text streamText
+	completedSteps := 0
+	stopped := false
 
-		// Re-marshal and parse into typed struct
-		eventData, err := json.Marshal(rawEvent)
-		if err != nil {
-			continue
-		}
-		typedEvent := parseEvent(eventData, eventType)
+	for event := range events {
+		eventType := mixEventType(event)
+		typedEvent := parseEvent([]byte(event.Data), eventType)
 		if typedEvent == nil {
 			continue
 		}
@@ -404,137 +667,376 @@ func (o *Orchestrator) collectEvents(eventsChan chan map[string]interface{}) ([]
 
 		case string(components.SSEEventStreamTypeToolExecutionStart):
 			evt := typedEvent.(*ToolExecutionStartEvent)
+			text.flush(logger)
+			if stopped {
+				continue
+			}
+			attrs := []any{"tool", "unknown"}
 			if toolInfo, exists := toolCallsMap[evt.ToolCallID]; exists {
-				fmt.Printf("\n🔧 %s\n", toolInfo.Name)
-				if evt.Progress != "" && evt.Progress != toolInfo.Name {
-					fmt.Printf("   %s\n", evt.Progress)
-				}
+				toolInfo.StartedAt = time.Now()
+				attrs = []any{"tool", toolInfo.Name}
 				if toolInfo.Parameters != "" {
-					var params map[string]interface{}
-					if err := json.Unmarshal([]byte(toolInfo.Parameters), &params); err == nil {
-						paramsJSON, _ := json.MarshalIndent(params, "   ", "  ")
-						fmt.Printf("   Parameters: %s\n", string(paramsJSON))
-					}
-				}
-			} else {
-				// Fallback if tool not in map
-				fmt.Printf("\n🔧 Tool\n")
-				if evt.Progress != "" {
-					fmt.Printf("   %s\n", evt.Progress)
+					attrs = append(attrs, "parameters", toolInfo.Parameters)
 				}
 			}
+			if evt.Progress != "" {
+				attrs = append(attrs, "progress", evt.Progress)
+			}
+			logger.Debug("tool started", attrs...)
 
 		case string(components.SSEEventStreamTypeToolExecutionComplete):
 			evt := typedEvent.(*ToolExecutionCompleteEvent)
+			if stopped {
+				continue
+			}
+			completedSteps++
+			endedAt := time.Now()
 			var toolName string
+			var startedAt time.Time
 			if toolInfo, exists := toolCallsMap[evt.ToolCallID]; exists {
 				toolName = toolInfo.Name
+				startedAt = toolInfo.StartedAt
 			}
 
 			if toolName != "" {
-				toolCalls = append(toolCalls, convex.ToolCall{
+				toolCall := convex.ToolCall{
 					ToolName: toolName,
 					Result:   evt.Progress,
 					IsError:  !evt.Success,
-				})
+					EndedAt:  endedAt.UnixMilli(),
+				}
+				var duration time.Duration
+				if !startedAt.IsZero() {
+					duration = endedAt.Sub(startedAt)
+					// Derived from the recorded timestamps so the three always agree
+					toolCall.StartedAt = startedAt.UnixMilli()
+					toolCall.DurationMs = toolCall.EndedAt - toolCall.StartedAt
+				}
+				toolCalls = append(toolCalls, toolCall)
 
-				// Display completion status
+				text.flush(logger)
 				if evt.Success {
-					fmt.Printf("   ✓ Completed\n")
+					logger.Debug("tool completed", "tool", toolName, "duration", duration)
 				} else {
-					fmt.Printf("   ✗ Failed: %s\n", evt.Progress)
+					logger.Debug("tool failed", "tool", toolName, "error", evt.Progress, "duration", duration)
 				}
 			}
+			if maxSteps > 0 && completedSteps >= maxSteps {
+				stopped = true
+				stop()
+			}
 
 		case string(components.SSEEventStreamTypeThinking):
 			evt := typedEvent.(*ThinkingEvent)
-			if evt.Content != "" {
-				if !thinkingActive {
-					fmt.Print("\n" + ANSIColorGray)
-					thinkingActive = true
-				}
-				fmt.Print(evt.Content)
-			}
+			text.add(logger, "agent thinking", evt.Content)
 
 		case string(components.SSEEventStreamTypeContent):
 			evt := typedEvent.(*ContentEvent)
-			if evt.Content != "" {
-				if thinkingActive {
-					fmt.Print(ANSIColorReset + "\n")
-					thinkingActive = false
-				}
-				fmt.Print(evt.Content)
-			}
+			text.add(logger, "agent response", evt.Content)
 
 		case string(components.SSEEventStreamTypeError):
 			evt := typedEvent.(*ErrorEvent)
-			if thinkingActive {
-				fmt.Print(ANSIColorReset + "\n")
-				thinkingActive = false
-			}
+			text.flush(logger)
 			if evt.Error != "" {
-				fmt.Printf("\n❌ Error: %s\n", evt.Error)
+				logger.Warn("agent error", "error", evt.Error)
 			}
 		}
 	}
 
-	// Clean up any dangling gray text
-	if thinkingActive {
-		fmt.Print(ANSIColorReset + "\n")
-	}
+	text.flush(logger)
 
 	return toolCalls, screenshots
 }
 
-// createBrowserSession creates browser session based on provider
-func (o *Orchestrator) createBrowserSession(provider string) (*providers.BrowserSession, error) {
-	switch provider {
-	case "browserbase":
-		return providers.CreateBrowserbaseSession(o.config.BrowserbaseKey, "default-project")
-	case "brightdata":
-		return providers.CreateBrightdataSession(o.config.BrightdataUser, o.config.BrightdataPass)
-	default:
-		return nil, fmt.Errorf("unknown browser provider: %s", provider)
+// RunMultipleTasks runs multiple tasks in parallel and summarizes the run.
+// Mix's agent model is restored when it returns if a task overrode it.
+//
+// Cancelling ctx starts a graceful shutdown: no new tasks are started, and
+// in-flight tasks get Config.ShutdownGrace to finish and save their results
+// before they are cancelled (Abort cancels them immediately). The summary is
+// returned even when the run is interrupted.
+func (o *Orchestrator) RunMultipleTasks(ctx context.Context, tasks []convex.Task, parallelism int) (*RunSummary, error) {
+	startedAt := time.Now()
+	tasks = expandTrials(tasks, o.config.Trials)
+
+	plan, err := o.openRun(ctx, tasks)
+	if err != nil {
+		return nil, err
 	}
+
+	o.warmBrowserPools(ctx, plan.tasks, parallelism)
+	defer o.closeBrowserPools(context.WithoutCancel(ctx))
+	defer o.restoreModel(context.WithoutCancel(ctx))
+
+	summary, err := o.runTasks(ctx, plan, parallelism, o.config.Trials, o.runTask)
+	summary.StartedAt = startedAt
+	return summary, err
 }
 
-// closeBrowserSession closes browser session
-func (o *Orchestrator) closeBrowserSession(session *providers.BrowserSession) {
-	switch session.Provider {
-	case providers.ProviderBrowserbase:
-		providers.CloseBrowserSession(session, o.config.BrowserbaseKey)
+// taskRunner produces the result of one task
+type taskRunner func(ctx context.Context, task convex.Task) (*convex.TaskResult, error)
+
+// runPlan is the work left in a run
+type runPlan struct {
+	tasks       []convex.Task
+	checkpoints map[string]*Checkpoint
+	// done are the outcomes of the tasks a resumed run skips, read back from
+	// the sink so the summary covers the whole run
+	done []taskOutcome
+	// partial is set when some of those outcomes could not be read back
+	partial bool
+}
+
+// openRun opens the checkpoint ledgers of a run and, when resuming, drops the
+// tasks they record as completed
+func (o *Orchestrator) openRun(ctx context.Context, tasks []convex.Task) (*runPlan, error) {
+	checkpoints, err := o.openCheckpoints(tasks)
+	if err != nil {
+		return nil, err
+	}
+	plan := &runPlan{tasks: tasks, checkpoints: checkpoints}
+	if o.config.Resume {
+		var completed []convex.Task
+		plan.tasks, completed = pendingTasks(o.logger, tasks, checkpoints)
+		plan.done, plan.partial = o.savedOutcomes(ctx, completed)
 	}
+	return plan, nil
 }
 
-// RunMultipleTasks runs multiple tasks in parallel
-func (o *Orchestrator) RunMultipleTasks(ctx context.Context, tasks []convex.Task, parallelism int) error {
+// savedOutcomes reads back the saved results of tasks completed by earlier
+// attempts; partial is set when some of them cannot be read back
+func (o *Orchestrator) savedOutcomes(ctx context.Context, completed []convex.Task) (outcomes []taskOutcome, partial bool) {
+	if len(completed) == 0 {
+		return nil, false
+	}
+	source, ok := o.sink.(ResultSource)
+	if !ok {
+		o.logger.Warn("sink cannot read results back, the summary covers only the tasks run now")
+		return nil, true
+	}
+
+	saved := make(map[string]map[string]convex.TaskResult)
+	for _, t := range completed {
+		results, ok := saved[t.RunID]
+		if !ok {
+			fetched, err := source.FetchRunResults(ctx, t.RunID)
+			if err != nil {
+				o.logger.Warn("reading back saved results failed, the summary covers only the tasks run now", "run_id", t.RunID, "error", err)
+				return nil, true
+			}
+			// The last saved result of a task wins
+			results = make(map[string]convex.TaskResult, len(fetched))
+			for _, r := range fetched {
+				results[checkpointKey(convex.Task{ID: r.TaskID, Trial: r.Trial})] = r
+			}
+			saved[t.RunID] = results
+		}
+
+		result, ok := results[checkpointKey(t)]
+		if !ok {
+			o.logger.Warn("saved result not found, leaving it out of the summary", "task_id", t.ID, "trial", t.Trial)
+			partial = true
+			continue
+		}
+		outcomes = append(outcomes, taskOutcome{
+			task:     t,
+			result:   &result,
+			saved:    true,
+			duration: time.Duration(result.DurationMs) * time.Millisecond,
+		})
+	}
+	return outcomes, partial
+}
+
+// runTasks runs a plan's tasks in parallel with run, saves their results and
+// summarizes them with the plan's earlier outcomes, draining in-flight tasks
+// when ctx is cancelled
+func (o *Orchestrator) runTasks(ctx context.Context, plan *runPlan, parallelism, trials int, run taskRunner) (*RunSummary, error) {
+	startedAt := time.Now()
+	tasks, checkpoints := plan.tasks, plan.checkpoints
+
+	// In-flight tasks run detached from ctx so they can drain after a shutdown request
+	taskCtx, cancelTasks := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelTasks()
+	o.setAbort(cancelTasks)
+	defer o.setAbort(nil)
+
 	var wg sync.WaitGroup
+	var outcomes outcomeLog
+	abandoned := 0
 	sem := make(chan struct{}, parallelism)
 
-	for _, task := range tasks {
-		wg.Add(1)
-		sem <- struct{}{}
+	for i, task := range tasks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			abandoned = len(tasks) - i
+			break
+		}
 
+		wg.Add(1)
 		go func(t convex.Task) {
 			defer wg.Done()
 			defer func() { <-sem }()
-
-			result, err := o.RunTask(ctx, t)
-			if err != nil {
-				fmt.Printf("Task %s failed: %v\n", t.ID, err)
-				return
-			}
-
-			if err := o.convexClient.SaveTaskResult(ctx, result); err != nil {
-				fmt.Printf("Failed to save result for %s: %v\n", t.ID, err)
-			} else {
-				fmt.Printf("Task %s completed: Score=%.2f\n", t.ID, result.Evaluation.Score)
-			}
+			outcomes.add(o.runAndSave(taskCtx, t, checkpoints, run))
 		}(task)
 	}
 
+	done := make(chan struct{})
+	go o.drainOnShutdown(ctx, done, cancelTasks)
 	wg.Wait()
-	return nil
+	close(done)
+
+	all := append(slices.Clone(plan.done), outcomes.outcomes...)
+	runID := ""
+	if len(tasks) > 0 {
+		runID = tasks[0].RunID
+	} else if len(all) > 0 {
+		runID = all[0].task.RunID
+	}
+	summary := summarize(runID, all, abandoned, trials)
+	summary.Resumed = len(plan.done)
+	summary.Partial = plan.partial
+	summary.StartedAt = startedAt
+	summary.FinishedAt = time.Now()
+	summary.Interrupted = ctx.Err() != nil
+
+	if summary.Interrupted {
+		return summary, fmt.Errorf("run interrupted: %w", ctx.Err())
+	}
+	return summary, nil
+}
+
+// runAndSave runs one task (with retries), saves its result and records the
+// transition in the checkpoint ledger
+func (o *Orchestrator) runAndSave(ctx context.Context, t convex.Task, checkpoints map[string]*Checkpoint, run taskRunner) taskOutcome {
+	logger, closeLog := o.taskLogger(t)
+	defer closeLog()
+	ctx = withLogger(ctx, logger)
+
+	markCheckpoint(logger, checkpoints, t, TaskStatusInFlight, nil)
+
+	start := time.Now()
+	result, err := o.runTaskWithRetry(ctx, t, run)
+	outcome := taskOutcome{task: t, result: result, duration: time.Since(start)}
+	if err != nil {
+		logger.Warn("task cancelled", "error", err)
+		markCheckpoint(logger, checkpoints, t, TaskStatusFailed, err)
+		return outcome
+	}
+	result.Category = t.Category
+	result.DurationMs = outcome.duration.Milliseconds()
+
+	// Flush the result even if the run is being force-stopped
+	if err := o.sink.SaveTaskResult(context.WithoutCancel(ctx), result); err != nil {
+		logger.Error("failed to save result", "error", err)
+		markCheckpoint(logger, checkpoints, t, TaskStatusFailed, err)
+		return outcome
+	}
+	outcome.saved = true
+
+	if result.InfraError != "" {
+		markCheckpoint(logger, checkpoints, t, TaskStatusErrored, errors.New(result.InfraError))
+	} else {
+		logger.Info("task completed", "score", result.Evaluation.Score, "passed", result.Evaluation.Passed)
+		markCheckpoint(logger, checkpoints, t, TaskStatusCompleted, nil)
+	}
+	return outcome
+}
+
+// drainOnShutdown waits for a shutdown request and cancels in-flight tasks
+// once the grace period expires, unless they finish first.
+func (o *Orchestrator) drainOnShutdown(ctx context.Context, done <-chan struct{}, cancelTasks context.CancelFunc) {
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+	}
+
+	o.logger.Info("shutdown requested, waiting for in-flight tasks", "grace", o.config.ShutdownGrace)
+	timer := time.NewTimer(o.config.ShutdownGrace)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		o.logger.Warn("shutdown grace period expired, cancelling in-flight tasks")
+		cancelTasks()
+	}
+}
+
+// setAbort registers the cancel function for the current run's tasks
+func (o *Orchestrator) setAbort(cancel context.CancelFunc) {
+	o.abortMu.Lock()
+	defer o.abortMu.Unlock()
+	o.abort = cancel
+}
+
+// Abort cancels all in-flight tasks immediately, skipping the shutdown grace
+// period. Browser and Mix sessions are still cleaned up.
+func (o *Orchestrator) Abort() {
+	o.abortMu.Lock()
+	defer o.abortMu.Unlock()
+	if o.abort != nil {
+		o.abort()
+	}
+}
+
+// openCheckpoints opens the checkpoint ledger of every run the tasks belong to.
+// Checkpointing is disabled when no output directory is configured.
+func (o *Orchestrator) openCheckpoints(tasks []convex.Task) (map[string]*Checkpoint, error) {
+	checkpoints := make(map[string]*Checkpoint)
+	if o.config.OutputDir == "" {
+		return checkpoints, nil
+	}
+	for _, t := range tasks {
+		if _, ok := checkpoints[t.RunID]; ok {
+			continue
+		}
+		checkpoint, err := OpenCheckpoint(o.config.OutputDir, t.RunID)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint for run %s: %w", t.RunID, err)
+		}
+		checkpoints[t.RunID] = checkpoint
+	}
+	return checkpoints, nil
+}
+
+// pendingTasks drops tasks the checkpoint ledger records as completed. Tasks
+// saved with an infra error are run again (the newer result replaces theirs,
+// as the last saved result wins), as are in-flight and failed tasks, which
+// have no saved result.
+func pendingTasks(logger *slog.Logger, tasks []convex.Task, checkpoints map[string]*Checkpoint) (pending, completed []convex.Task) {
+	for _, t := range tasks {
+		checkpoint, ok := checkpoints[t.RunID]
+		if !ok {
+			pending = append(pending, t)
+			continue
+		}
+		switch checkpoint.Status(t) {
+		case TaskStatusCompleted:
+			completed = append(completed, t)
+			continue
+		case TaskStatusInFlight, TaskStatusFailed, TaskStatusErrored:
+			logger.Info("retrying task from previous attempt", "task_id", t.ID, "status", checkpoint.Status(t))
+		}
+		pending = append(pending, t)
+	}
+	logger.Info("resuming run", "pending", len(pending), "total", len(tasks))
+	return pending, completed
+}
+
+// markCheckpoint records a task status transition, warning on ledger errors
+func markCheckpoint(logger *slog.Logger, checkpoints map[string]*Checkpoint, task convex.Task, status TaskStatus, cause error) {
+	checkpoint, ok := checkpoints[task.RunID]
+	if !ok {
+		return
+	}
+	if err := checkpoint.Mark(task, status, cause); err != nil {
+		logger.Warn("failed to checkpoint task", "status", status, "error", err)
+	}
 }
 
 // convertToJudgeToolCalls converts ToolCallDetail to ToolCall for the judge
@@ -561,6 +1063,7 @@ func convertToJudgeToolCalls(details []ToolCallDetail) []ToolCall {
 // fetchScreenshots fetches screenshots from Mix over HTTP and returns raw bytes.
 // Relative URLs are resolved against mixBaseURL.
 func fetchScreenshots(ctx context.Context, mixBaseURL string, urls []string) [][]byte {
+	logger := loggerFrom(ctx)
 	var results [][]byte
 	for _, rawURL := range urls {
 		fullURL := rawURL
@@ -569,18 +1072,18 @@ func fetchScreenshots(ctx context.Context, mixBaseURL string, urls []string) [][
 		}
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, nil)
 		if err != nil {
-			fmt.Printf("Warning: could not build screenshot request for %s: %v\n", fullURL, err)
+			logger.Warn("could not build screenshot request", "url", fullURL, "error", err)
 			continue
 		}
 		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
-			fmt.Printf("Warning: failed to fetch screenshot %s: %v\n", fullURL, err)
+			logger.Warn("failed to fetch screenshot", "url", fullURL, "error", err)
 			continue
 		}
 		data, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
-			fmt.Printf("Warning: failed to read screenshot body %s: %v\n", fullURL, err)
+			logger.Warn("failed to read screenshot body", "url", fullURL, "error", err)
 			continue
 		}
 		results = append(results, data)
@@ -0,0 +1,206 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/recreate-run/mix-go-sdk"
+
+	"mix-eval-go/pkg/convex"
+	"mix-eval-go/pkg/sse"
+)
+
+func TestCollectEventsRecordsToolTiming(t *testing.T) {
+	events := make(chan sse.Event)
+	go func() {
+		defer close(events)
+		events <- sse.Event{Type: "tool_use_start", Data: `{"id":"t1","name":"python"}`}
+		events <- sse.Event{Type: "tool_execution_start", Data: `{"toolCallId":"t1"}`}
+		time.Sleep(30 * time.Millisecond)
+		events <- sse.Event{Type: "tool_execution_complete", Data: `{"toolCallId":"t1","success":true}`}
+	}()
+
+	o := &Orchestrator{}
+	toolCalls, _ := o.collectEvents(slog.Default(), events, 0, nil)
+
+	if len(toolCalls) != 1 {
+		t.Fatalf("Expected 1 tool call, got %d", len(toolCalls))
+	}
+	tc := toolCalls[0]
+	if tc.StartedAt == 0 || tc.EndedAt < tc.StartedAt {
+		t.Errorf("Unexpected timestamps: started %d ended %d", tc.StartedAt, tc.EndedAt)
+	}
+	if tc.DurationMs < 30 || tc.DurationMs != tc.EndedAt-tc.StartedAt {
+		t.Errorf("Unexpected duration %dms for %d..%d", tc.DurationMs, tc.StartedAt, tc.EndedAt)
+	}
+}
+
+func TestTimeToFirstAction(t *testing.T) {
+	sentAt := time.UnixMilli(1_000_000)
+	toolCalls := []convex.ToolCall{
+		{ToolName: "browser_state", StartedAt: 1_004_000},
+		{ToolName: "python", StartedAt: 1_002_500},
+		{ToolName: "unstarted"},
+	}
+	if got := timeToFirstAction(sentAt, toolCalls); got != 2500*time.Millisecond {
+		t.Errorf("Expected 2.5s to first action, got %s", got)
+	}
+	if got := timeToFirstAction(sentAt, nil); got != 0 {
+		t.Errorf("Expected 0 without tool calls, got %s", got)
+	}
+}
+
+func TestStoreHistory(t *testing.T) {
+	history := []map[string]interface{}{{"id": "m1", "role": "assistant", "assistantResponse": "done"}}
+
+	var uploaded []byte
+	uploadFails := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/generateUploadUrl":
+			json.NewEncoder(w).Encode(map[string]string{"uploadUrl": "http://" + r.Host + "/upload"})
+		case "/upload":
+			if uploadFails {
+				http.Error(w, "storage unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			uploaded, _ = io.ReadAll(r.Body)
+			json.NewEncoder(w).Encode(map[string]string{"storageId": "history-1"})
+		}
+	}))
+	defer srv.Close()
+
+	offline := &Orchestrator{}
+	if id, inline := offline.storeHistory(context.Background(), history); id != "" || len(inline) != 1 {
+		t.Errorf("Expected the history inline without Convex, got id %q and %d messages", id, len(inline))
+	}
+
+	o := &Orchestrator{config: Config{ConvexURL: srv.URL}, convexClient: convex.NewClient(srv.URL, "secret")}
+	id, inline := o.storeHistory(context.Background(), history)
+	if id != "history-1" || inline != nil {
+		t.Errorf("Expected only the storage ID, got id %q and %d inline messages", id, len(inline))
+	}
+	var stored []map[string]interface{}
+	if err := json.Unmarshal(uploaded, &stored); err != nil || len(stored) != 1 || stored[0]["assistantResponse"] != "done" {
+		t.Errorf("Expected the history to be uploaded as JSON, got %s", uploaded)
+	}
+
+	uploadFails = true
+	if id, inline := o.storeHistory(context.Background(), history); id != "" || inline != nil {
+		t.Errorf("Expected a failed upload to leave the history out, got id %q and %d messages", id, len(inline))
+	}
+}
+
+// promptJudgeLLM passes every task and records the prompts it was sent
+type promptJudgeLLM struct {
+	mu      sync.Mutex
+	prompts []string
+}
+
+func (p *promptJudgeLLM) Send(_ context.Context, messages []JudgeMessage) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prompts = append(p.prompts, messages[0].Content)
+	return `{"verdict": true, "reasoning": "navigated to the page"}`, nil
+}
+
+// stubMixHangingAgent serves a Mix session whose agent runs one tool and then
+// never completes. It returns the requests made, as "METHOD /path".
+func stubMixHangingAgent(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/sessions":
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"s-1","title":"task","browserMode":"local-browser-service","sessionType":"main",`+
+				`"createdAt":"2026-01-01T00:00:00Z","assistantMessageCount":0,"userMessageCount":0,`+
+				`"toolCallCount":0,"promptTokens":0,"completionTokens":0,"cost":0}`)
+		case "GET /stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: connected\nid: 1\ndata: {\"sessionId\":\"s-1\"}\n\n")
+			fmt.Fprint(w, "event: tool_use_start\nid: 2\ndata: {\"id\":\"t1\",\"name\":\"browser_navigate\"}\n\n")
+			fmt.Fprint(w, "event: tool_execution_complete\nid: 3\ndata: {\"toolCallId\":\"t1\",\"success\":true}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case "POST /api/sessions/s-1/messages":
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(w, `{"sessionId":"s-1","status":"processing"}`)
+		case "POST /api/sessions/s-1/cancel":
+			fmt.Fprint(w, `{"cancelled":true}`)
+		case "GET /api/sessions/s-1/messages":
+			fmt.Fprint(w, `[{"id":"m1","role":"assistant","sessionId":"s-1","userInput":"Find the title",`+
+				`"toolCalls":[{"id":"t1","name":"browser_navigate","input":"{\"url\":\"https://example.com\"}",`+
+				`"result":"ok","finished":true,"type":"tool_use"}]}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(requests)
+	}
+}
+
+func TestRunTaskTimeout(t *testing.T) {
+	srv, requests := stubMixHangingAgent(t)
+	outputDir := t.TempDir()
+	sink, err := NewLocalSink(outputDir)
+	if err != nil {
+		t.Fatalf("NewLocalSink failed: %v", err)
+	}
+	llm := &promptJudgeLLM{}
+	o := &Orchestrator{
+		config:    Config{MixURL: srv.URL, TaskTimeout: 200 * time.Millisecond},
+		mixClient: mix.New(srv.URL),
+		judge:     NewJudge(llm),
+		sink:      sink,
+		logger:    slog.Default(),
+	}
+	task := convex.Task{ID: "task-1", RunID: "run-1", Text: "Find the title"}
+
+	outcome := o.runAndSave(context.Background(), task, nil, o.runTask)
+	if !outcome.saved {
+		t.Fatalf("Expected the timed-out task to be judged and saved, got %+v", outcome)
+	}
+
+	if !slices.Contains(requests(), "POST /api/sessions/s-1/cancel") {
+		t.Errorf("Expected the Mix session to be cancelled, got requests %v", requests())
+	}
+	if len(llm.prompts) != 1 || !strings.Contains(llm.prompts[0], "browser_navigate") {
+		t.Errorf("Expected the judge to see the partial history, got prompts %q", llm.prompts)
+	}
+
+	results, err := sink.FetchRunResults(context.Background(), "run-1")
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected one saved result, got %d (%v)", len(results), err)
+	}
+	evaluation := results[0].Evaluation
+	if evaluation == nil || !slices.Contains(evaluation.Errors, convex.ErrorCategoryTimeout) {
+		t.Fatalf("Expected the timeout error category, got %+v", evaluation)
+	}
+	if !strings.HasPrefix(evaluation.Reasoning, "[Agent timed out after 200ms]") {
+		t.Errorf("Expected the reasoning to note the timeout, got %q", evaluation.Reasoning)
+	}
+	if len(results[0].ToolCalls) != 1 || results[0].ToolCalls[0].ToolName != "browser_navigate" {
+		t.Errorf("Expected the tool call made before the timeout, got %+v", results[0].ToolCalls)
+	}
+}
@@ -0,0 +1,391 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/recreate-run/mix-go-sdk/models/components"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// StoredExecution is a finished agent execution that can be judged again
+// without rerunning the agent
+type StoredExecution struct {
+	Task      convex.Task
+	ToolCalls []convex.ToolCall
+	// JudgeToolCalls are the tool calls shown to the judge, rebuilt from the
+	// message history as in the original run
+	JudgeToolCalls  []ToolCall
+	FinalResponse   string
+	Reasoning       string
+	Model           string
+	AgentUsage      *convex.TokenUsage
+	CompleteHistory []map[string]interface{}
+	// CompleteHistoryStorageID is set instead of CompleteHistory when the
+	// history is already in Convex storage
+	CompleteHistoryStorageID string
+	Trace                    *convex.TraceRef
+	// ScreenshotStorageIDs are reused by the new result instead of uploading
+	// the screenshots again
+	ScreenshotStorageIDs []string
+	// LoadScreenshots returns the screenshots shown to the judge; nil when
+	// none were stored
+	LoadScreenshots func(ctx context.Context) ([][]byte, error)
+}
+
+// LoadTraces loads the executions recorded in a run's traces, taking the
+// latest attempt at each task. Attempts that never got a message history,
+// and traces written before tasks were recorded, are skipped.
+func LoadTraces(outputDir, runID string) ([]StoredExecution, error) {
+	root := filepath.Join(runDir(outputDir, runID), tracesDirName)
+	taskDirs, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("read traces failed: %w", err)
+	}
+
+	var executions []StoredExecution
+	for _, taskDir := range taskDirs {
+		if !taskDir.IsDir() {
+			continue
+		}
+		dir, err := latestAttempt(filepath.Join(root, taskDir.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if dir == "" {
+			continue
+		}
+		execution, err := loadTrace(dir)
+		if err != nil {
+			return nil, err
+		}
+		executions = append(executions, *execution)
+	}
+	return executions, nil
+}
+
+// latestAttempt returns the attempt directory whose message history was
+// written last, or "" when no attempt is complete enough to judge
+func latestAttempt(taskDir string) (string, error) {
+	attempts, err := os.ReadDir(taskDir)
+	if err != nil {
+		return "", fmt.Errorf("read trace attempts failed: %w", err)
+	}
+
+	latest := ""
+	var latestMod int64
+	for _, attempt := range attempts {
+		if !attempt.IsDir() {
+			continue
+		}
+		dir := filepath.Join(taskDir, attempt.Name())
+		if _, err := os.Stat(filepath.Join(dir, traceTaskFile)); err != nil {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, traceMessagesFile))
+		if err != nil {
+			continue
+		}
+		if mod := info.ModTime().UnixNano(); latest == "" || mod > latestMod {
+			latest, latestMod = dir, mod
+		}
+	}
+	return latest, nil
+}
+
+// loadTrace builds a stored execution from one attempt's trace directory
+func loadTrace(dir string) (*StoredExecution, error) {
+	var task convex.Task
+	if err := readJSONFile(filepath.Join(dir, traceTaskFile), &task); err != nil {
+		return nil, fmt.Errorf("read trace task failed: %w", err)
+	}
+	var messages []components.BackendMessage
+	if err := readJSONFile(filepath.Join(dir, traceMessagesFile), &messages); err != nil {
+		return nil, fmt.Errorf("read trace messages failed: %w", err)
+	}
+
+	history := extractHistory(messages)
+	toolCalls := make([]convex.ToolCall, len(history.ToolCalls))
+	for i, detail := range history.ToolCalls {
+		toolCalls[i] = convex.ToolCall{
+			ToolName: detail.Name,
+			Args:     detail.Input,
+			Result:   detail.Result,
+			IsError:  detail.IsError,
+		}
+	}
+
+	return &StoredExecution{
+		Task:            task,
+		ToolCalls:       toolCalls,
+		JudgeToolCalls:  convertToJudgeToolCalls(history.ToolCalls),
+		FinalResponse:   history.FinalResponse,
+		Reasoning:       history.Reasoning,
+		Model:           history.Model,
+		AgentUsage:      history.agentUsage(),
+		CompleteHistory: completeHistory(messages),
+		Trace:           &convex.TraceRef{Path: dir},
+		LoadScreenshots: func(context.Context) ([][]byte, error) {
+			return readTraceScreenshots(dir)
+		},
+	}, nil
+}
+
+// readTraceScreenshots reads an attempt's screenshots in the order they were
+// shown to the judge
+func readTraceScreenshots(dir string) ([][]byte, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, traceScreenshotsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read trace screenshots failed: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	screenshots := make([][]byte, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, traceScreenshotsDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("read trace screenshot failed: %w", err)
+		}
+		screenshots = append(screenshots, data)
+	}
+	return screenshots, nil
+}
+
+// FetchRunExecutions loads the executions of a run saved in Convex. Results
+// without a verdict (infra errors) have nothing to judge and are skipped;
+// when a task was saved more than once, the last result wins.
+func (o *Orchestrator) FetchRunExecutions(ctx context.Context, runID string) ([]StoredExecution, error) {
+	results, err := o.convexClient.FetchRunResults(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("fetch run %s failed: %w", runID, err)
+	}
+
+	index := make(map[string]int)
+	var latest []convex.TaskResult
+	for _, result := range results {
+		if result.Evaluation == nil || result.InfraError != "" {
+			continue
+		}
+		key := checkpointKey(convex.Task{ID: result.TaskID, Trial: result.Trial})
+		if i, ok := index[key]; ok {
+			latest[i] = result
+			continue
+		}
+		index[key] = len(latest)
+		latest = append(latest, result)
+	}
+
+	executions := make([]StoredExecution, 0, len(latest))
+	for _, result := range latest {
+		execution, err := storedResult(ctx, o.convexClient, result)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", result.TaskID, err)
+		}
+		executions = append(executions, *execution)
+	}
+	return executions, nil
+}
+
+// storedResult builds a stored execution from a saved result, downloading its
+// screenshots from Convex storage when it is judged. The judge's tool calls
+// come from the saved message history: the result's own tool calls are
+// recorded from the event stream and lack arguments and tool output. Results
+// saved without a history (runs from before it was recorded) fall back to
+// those tool calls.
+func storedResult(ctx context.Context, client *convex.Client, result convex.TaskResult) (*StoredExecution, error) {
+	judgeToolCalls := resultToolCalls(result.ToolCalls)
+	reasoning := ""
+	messages, err := savedHistory(ctx, client, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) > 0 {
+		history := extractHistory(messages)
+		judgeToolCalls = convertToJudgeToolCalls(history.ToolCalls)
+		reasoning = history.Reasoning
+	}
+
+	storageIDs := result.ScreenshotStorageIDs
+	return &StoredExecution{
+		Task: convex.Task{
+			ID:       result.TaskID,
+			RunID:    result.RunID,
+			Text:     result.Task,
+			Category: result.Category,
+			Trial:    result.Trial,
+		},
+		ToolCalls:                result.ToolCalls,
+		JudgeToolCalls:           judgeToolCalls,
+		FinalResponse:            result.FinalResponse,
+		Reasoning:                reasoning,
+		Model:                    result.Model,
+		AgentUsage:               result.AgentUsage,
+		CompleteHistory:          result.CompleteHistory,
+		CompleteHistoryStorageID: result.CompleteHistoryStorageID,
+		Trace:                    result.Trace,
+		ScreenshotStorageIDs:     storageIDs,
+		LoadScreenshots: func(ctx context.Context) ([][]byte, error) {
+			screenshots := make([][]byte, 0, len(storageIDs))
+			for _, id := range storageIDs {
+				data, err := client.DownloadFile(ctx, id)
+				if err != nil {
+					return nil, fmt.Errorf("download screenshot %s failed: %w", id, err)
+				}
+				screenshots = append(screenshots, data)
+			}
+			return screenshots, nil
+		},
+	}, nil
+}
+
+// resultToolCalls converts a result's recorded tool calls for the judge; only
+// calls recorded with their arguments carry them
+func resultToolCalls(recorded []convex.ToolCall) []ToolCall {
+	toolCalls := make([]ToolCall, len(recorded))
+	for i, tc := range recorded {
+		var args map[string]interface{}
+		if tc.Args != "" {
+			if err := json.Unmarshal([]byte(tc.Args), &args); err != nil {
+				args = map[string]interface{}{"input": tc.Args}
+			}
+		}
+		toolCalls[i] = ToolCall{
+			ToolName:  tc.ToolName,
+			Arguments: args,
+			Result:    tc.Result,
+			IsError:   tc.IsError,
+		}
+	}
+	return toolCalls
+}
+
+// savedHistory returns a result's message history as Mix messages, downloading
+// it from Convex storage when it was saved there; nil when none was saved
+func savedHistory(ctx context.Context, client *convex.Client, result convex.TaskResult) ([]components.BackendMessage, error) {
+	var data []byte
+	switch {
+	case result.CompleteHistoryStorageID != "":
+		var err error
+		if data, err = client.DownloadFile(ctx, result.CompleteHistoryStorageID); err != nil {
+			return nil, fmt.Errorf("download message history failed: %w", err)
+		}
+	case len(result.CompleteHistory) > 0:
+		var err error
+		if data, err = json.Marshal(result.CompleteHistory); err != nil {
+			return nil, fmt.Errorf("encode message history failed: %w", err)
+		}
+	default:
+		return nil, nil
+	}
+
+	var messages []components.BackendMessage
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("decode message history failed: %w", err)
+	}
+	return messages, nil
+}
+
+func readJSONFile(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Rejudge evaluates stored executions again with the current judge and saves
+// the verdicts under newRunID, linked to the run they came from. Nothing is
+// sent to Mix or a browser provider.
+func (o *Orchestrator) Rejudge(ctx context.Context, executions []StoredExecution, newRunID string, parallelism int) (*RunSummary, error) {
+	byKey := make(map[string]StoredExecution, len(executions))
+	tasks := make([]convex.Task, 0, len(executions))
+	trials := 1
+	sourceRunID := ""
+	for i, execution := range executions {
+		task := execution.Task
+		task.RunID = newRunID
+		byKey[checkpointKey(task)] = execution
+		tasks = append(tasks, task)
+		trials = max(trials, task.Trial)
+
+		// The summary links to the source run only when there is exactly one
+		if i == 0 {
+			sourceRunID = execution.Task.RunID
+		} else if execution.Task.RunID != sourceRunID {
+			sourceRunID = ""
+		}
+	}
+
+	plan, err := o.openRun(ctx, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	rejudge := func(ctx context.Context, task convex.Task) (*convex.TaskResult, error) {
+		return o.rejudgeTask(ctx, task, byKey[checkpointKey(task)])
+	}
+	summary, err := o.runTasks(ctx, plan, parallelism, trials, rejudge)
+	summary.SourceRunID = sourceRunID
+	return summary, err
+}
+
+// rejudgeTask judges one stored execution and builds its result
+func (o *Orchestrator) rejudgeTask(ctx context.Context, task convex.Task, execution StoredExecution) (*convex.TaskResult, error) {
+	var screenshots [][]byte
+	if execution.LoadScreenshots != nil {
+		var err error
+		if screenshots, err = execution.LoadScreenshots(ctx); err != nil {
+			return nil, infraError("load screenshots", err)
+		}
+	}
+	loggerFrom(ctx).Info("rejudging task", "tool_calls", len(execution.JudgeToolCalls), "screenshots", len(screenshots))
+
+	verdict, err := o.judgeExecution(ctx, task, execution.JudgeToolCalls,
+		execution.FinalResponse, execution.Reasoning, screenshots)
+	if err != nil {
+		return nil, err
+	}
+
+	storageIDs := execution.ScreenshotStorageIDs
+	if len(storageIDs) == 0 && o.config.ConvexURL != "" {
+		storageIDs, _ = o.convexClient.UploadScreenshots(ctx, screenshots)
+	}
+	historyID, inlineHistory := execution.CompleteHistoryStorageID, execution.CompleteHistory
+	if historyID == "" {
+		historyID, inlineHistory = o.storeHistory(ctx, inlineHistory)
+	}
+
+	return &convex.TaskResult{
+		RunID:                    task.RunID,
+		TaskID:                   task.ID,
+		Trial:                    task.Trial,
+		Task:                     task.Text,
+		ToolCalls:                execution.ToolCalls,
+		ScreenshotStorageIDs:     storageIDs,
+		FinalResponse:            execution.FinalResponse,
+		Evaluation:               verdict.evaluation,
+		CompleteHistory:          inlineHistory,
+		CompleteHistoryStorageID: historyID,
+		Trace:                    execution.Trace,
+		Model:                    execution.Model,
+		AgentUsage:               execution.AgentUsage,
+		JudgeUsage:               verdict.usage,
+		JudgeDurationMs:          verdict.duration.Milliseconds(),
+		SourceRunID:              execution.Task.RunID,
+	}, nil
+}
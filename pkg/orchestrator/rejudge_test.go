@@ -0,0 +1,320 @@
+package orchestrator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// stubJudgeLLM passes every task and records the images it was shown
+type stubJudgeLLM struct {
+	mu     sync.Mutex
+	images int
+}
+
+func (s *stubJudgeLLM) Send(_ context.Context, messages []JudgeMessage) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.images += len(messages[0].Images)
+	return `{"verdict": true, "reasoning": "looks done"}`, nil
+}
+
+func pngScreenshot(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatalf("png.Encode failed: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// writeTestTrace records a finished attempt the way RunTask does
+func writeTestTrace(t *testing.T, outputDir string, task convex.Task, sessionID, answer string, screenshots [][]byte) {
+	t.Helper()
+	trace, err := newTraceWriter(traceDir(outputDir, task, sessionID))
+	if err != nil {
+		t.Fatalf("newTraceWriter failed: %v", err)
+	}
+	defer trace.close()
+	if err := trace.writeTask(task); err != nil {
+		t.Fatalf("writeTask failed: %v", err)
+	}
+	messages := []map[string]interface{}{{
+		"id": "m1", "role": "assistant", "sessionId": sessionID, "userInput": task.Text,
+		"assistantResponse": answer,
+		"model":             "agent-model",
+		"toolCalls": []map[string]interface{}{{
+			"id": "t1", "name": "browser_navigate", "input": `{"url":"https://example.com"}`,
+			"result": "ok", "finished": true, "type": "tool_use",
+		}},
+	}}
+	if err := trace.writeMessages(messages); err != nil {
+		t.Fatalf("writeMessages failed: %v", err)
+	}
+	if err := trace.writeScreenshots(screenshots); err != nil {
+		t.Fatalf("writeScreenshots failed: %v", err)
+	}
+}
+
+func TestLoadTraces(t *testing.T) {
+	outputDir := t.TempDir()
+	task := convex.Task{ID: "task-1", RunID: "run-1", Text: "Find the title"}
+	screenshot := pngScreenshot(t)
+
+	writeTestTrace(t, outputDir, task, "session-old", "first try", nil)
+	time.Sleep(10 * time.Millisecond)
+	writeTestTrace(t, outputDir, task, "session-new", "Example Domain", [][]byte{screenshot, screenshot})
+
+	// An attempt that never got its message history is ignored
+	if err := os.MkdirAll(traceDir(outputDir, task, "session-broken"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	executions, err := LoadTraces(outputDir, "run-1")
+	if err != nil {
+		t.Fatalf("LoadTraces failed: %v", err)
+	}
+	if len(executions) != 1 {
+		t.Fatalf("Expected 1 execution, got %d", len(executions))
+	}
+
+	execution := executions[0]
+	if execution.Task.ID != "task-1" || execution.Task.Text != "Find the title" {
+		t.Errorf("Unexpected task: %+v", execution.Task)
+	}
+	if execution.FinalResponse != "Example Domain" {
+		t.Errorf("Expected the latest attempt, got final response %q", execution.FinalResponse)
+	}
+	if execution.Model != "agent-model" {
+		t.Errorf("Expected model agent-model, got %q", execution.Model)
+	}
+	if len(execution.ToolCalls) != 1 || execution.ToolCalls[0].ToolName != "browser_navigate" {
+		t.Errorf("Unexpected tool calls: %+v", execution.ToolCalls)
+	}
+	if execution.Trace == nil || filepath.Base(execution.Trace.Path) != "session-new" {
+		t.Errorf("Unexpected trace: %+v", execution.Trace)
+	}
+
+	screenshots, err := execution.LoadScreenshots(context.Background())
+	if err != nil {
+		t.Fatalf("LoadScreenshots failed: %v", err)
+	}
+	if len(screenshots) != 2 || !bytes.Equal(screenshots[0], screenshot) {
+		t.Errorf("Expected 2 stored screenshots, got %d", len(screenshots))
+	}
+}
+
+func TestLoadTracesMissingRun(t *testing.T) {
+	if _, err := LoadTraces(t.TempDir(), "missing"); err == nil {
+		t.Error("Expected an error for a run without traces")
+	}
+}
+
+func TestRejudge(t *testing.T) {
+	outputDir := t.TempDir()
+	screenshot := pngScreenshot(t)
+	for trial := 1; trial <= 2; trial++ {
+		task := convex.Task{ID: "task-1", RunID: "run-1", Trial: trial, Text: "Find the title"}
+		writeTestTrace(t, outputDir, task, "session", "Example Domain", [][]byte{screenshot})
+	}
+
+	executions, err := LoadTraces(outputDir, "run-1")
+	if err != nil {
+		t.Fatalf("LoadTraces failed: %v", err)
+	}
+
+	sink, err := NewLocalSink(outputDir)
+	if err != nil {
+		t.Fatalf("NewLocalSink failed: %v", err)
+	}
+	llm := &stubJudgeLLM{}
+	o := &Orchestrator{
+		config: Config{OutputDir: outputDir},
+		judge:  NewJudge(llm),
+		sink:   sink,
+		logger: slog.Default(),
+	}
+
+	summary, err := o.Rejudge(context.Background(), executions, "run-1-rejudged", 2)
+	if err != nil {
+		t.Fatalf("Rejudge failed: %v", err)
+	}
+	if summary.RunID != "run-1-rejudged" || summary.Total != 2 || summary.Passed != 2 {
+		t.Errorf("Unexpected summary: run %s, %d total, %d passed", summary.RunID, summary.Total, summary.Passed)
+	}
+	if summary.TrialsK != 2 {
+		t.Errorf("Expected 2 trials per task, got %d", summary.TrialsK)
+	}
+	if llm.images != 2 {
+		t.Errorf("Expected the judge to see 2 screenshots, saw %d", llm.images)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "run-1-rejudged", resultsFileName))
+	if err != nil {
+		t.Fatalf("Read rejudged results failed: %v", err)
+	}
+	var result convex.TaskResult
+	if err := json.Unmarshal(bytes.SplitN(data, []byte("\n"), 2)[0], &result); err != nil {
+		t.Fatalf("Invalid result line: %v", err)
+	}
+	if result.TaskID != "task-1" || result.Evaluation == nil || !result.Evaluation.Passed {
+		t.Errorf("Unexpected rejudged result: %+v", result)
+	}
+	if result.FinalResponse != "Example Domain" || len(result.ToolCalls) != 1 || result.Model != "agent-model" {
+		t.Errorf("Rejudged result lost the stored execution: %+v", result)
+	}
+}
+
+func TestRejudgeConvexRun(t *testing.T) {
+	screenshot := pngScreenshot(t)
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/getRunResults":
+			var req struct {
+				RunID string `json:"runId"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.RunID != "run-1" {
+				t.Errorf("Expected run-1 to be fetched, got %q", req.RunID)
+			}
+			// Saved the way RunTask saves them: the tool calls come from the
+			// event stream (no arguments, progress text as the result), the
+			// judge's evidence from the message history in Convex storage
+			json.NewEncoder(w).Encode([]convex.TaskResult{
+				{RunID: "run-1", TaskID: "task-1", Task: "Find the title", FinalResponse: "stale", Evaluation: &convex.Evaluation{}},
+				{
+					RunID: "run-1", TaskID: "task-1", Task: "Find the title", Category: "search",
+					FinalResponse: "Example Domain",
+					ToolCalls: []convex.ToolCall{{
+						ToolName: "browser_navigate", Result: "Navigating",
+						StartedAt: 1700000000000, EndedAt: 1700000000500, DurationMs: 500,
+					}},
+					ScreenshotStorageIDs:     []string{"shot-1"},
+					CompleteHistoryStorageID: "history-1",
+					Evaluation:               &convex.Evaluation{Passed: false},
+				},
+				{RunID: "run-1", TaskID: "task-2", Task: "Broken", InfraError: "session creation failed", Evaluation: &convex.Evaluation{}},
+				// Saved before the message history was recorded
+				{
+					RunID: "run-1", TaskID: "task-3", Task: "Search for shoes", FinalResponse: "Found 12 results",
+					ToolCalls: []convex.ToolCall{
+						{ToolName: "browser_type", Args: `{"text":"shoes"}`, Result: "Typed"},
+						{ToolName: "browser_click", Result: "Clicked", IsError: true},
+					},
+					Evaluation: &convex.Evaluation{},
+				},
+			})
+		case "/api/getStorageUrl":
+			var req struct {
+				StorageID string `json:"storageId"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			json.NewEncoder(w).Encode(map[string]string{"url": srv.URL + "/files/" + req.StorageID})
+		case "/files/shot-1":
+			w.Write(screenshot)
+		case "/files/history-1":
+			json.NewEncoder(w).Encode([]map[string]interface{}{{
+				"id": "m1", "role": "assistant", "sessionId": "session", "userInput": "Find the title",
+				"assistantResponse": "Example Domain",
+				"reasoning":         "title is on the page",
+				"toolCalls": []map[string]interface{}{{
+					"id": "t1", "name": "browser_navigate", "input": `{"url":"https://example.com"}`,
+					"result": "Loaded page titled Example Domain", "finished": true, "type": "tool_use",
+				}},
+			}})
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	outputDir := t.TempDir()
+	sink, err := NewLocalSink(outputDir)
+	if err != nil {
+		t.Fatalf("NewLocalSink failed: %v", err)
+	}
+	llm := &stubJudgeLLM{}
+	o := &Orchestrator{
+		config:       Config{OutputDir: outputDir},
+		convexClient: convex.NewClient(srv.URL, "secret"),
+		judge:        NewJudge(llm),
+		sink:         sink,
+		logger:       slog.Default(),
+	}
+
+	executions, err := o.FetchRunExecutions(context.Background(), "run-1")
+	if err != nil {
+		t.Fatalf("FetchRunExecutions failed: %v", err)
+	}
+	if len(executions) != 2 {
+		t.Fatalf("Expected 2 judgeable executions, got %d", len(executions))
+	}
+	if executions[0].FinalResponse != "Example Domain" || executions[0].Reasoning != "title is on the page" {
+		t.Errorf("Expected the last saved result with its reasoning, got %+v", executions[0])
+	}
+	judged := executions[0].JudgeToolCalls
+	if len(judged) != 1 || judged[0].Arguments["url"] != "https://example.com" || judged[0].Result != "Loaded page titled Example Domain" {
+		t.Errorf("Expected the judge's tool calls from the message history, got %+v", judged)
+	}
+	legacy := executions[1]
+	if legacy.Task.ID != "task-3" || legacy.FinalResponse != "Found 12 results" {
+		t.Errorf("Expected the result saved without a history, got %+v", legacy)
+	}
+	if judged := legacy.JudgeToolCalls; len(judged) != 2 ||
+		judged[0].Arguments["text"] != "shoes" || judged[0].Result != "Typed" ||
+		judged[1].Arguments != nil || !judged[1].IsError {
+		t.Errorf("Expected the judge's tool calls from the saved tool calls, got %+v", judged)
+	}
+
+	summary, err := o.Rejudge(context.Background(), executions, "run-1-v2", 1)
+	if err != nil {
+		t.Fatalf("Rejudge failed: %v", err)
+	}
+	if summary.SourceRunID != "run-1" || summary.Passed != 2 {
+		t.Errorf("Unexpected summary: source %q, %d passed", summary.SourceRunID, summary.Passed)
+	}
+	if llm.images != 1 {
+		t.Errorf("Expected the judge to see the downloaded screenshot, saw %d images", llm.images)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outputDir, "run-1-v2", resultsFileName))
+	if err != nil {
+		t.Fatalf("Read rejudged results failed: %v", err)
+	}
+	results := make(map[string]convex.TaskResult)
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		var result convex.TaskResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			t.Fatalf("Invalid result line: %v", err)
+		}
+		results[result.TaskID] = result
+	}
+	if legacy := results["task-3"]; legacy.Evaluation == nil || !legacy.Evaluation.Passed || len(legacy.ToolCalls) != 2 {
+		t.Errorf("Expected the result saved without a history to be rejudged, got %+v", legacy)
+	}
+	result := results["task-1"]
+	if result.SourceRunID != "run-1" || result.Category != "search" {
+		t.Errorf("Unexpected rejudged result: %+v", result)
+	}
+	if len(result.ScreenshotStorageIDs) != 1 || result.ScreenshotStorageIDs[0] != "shot-1" {
+		t.Errorf("Expected the stored screenshots to be reused, got %v", result.ScreenshotStorageIDs)
+	}
+	if result.CompleteHistoryStorageID != "history-1" || result.CompleteHistory != nil {
+		t.Errorf("Expected the stored message history to be reused, got %q and %d inline messages",
+			result.CompleteHistoryStorageID, len(result.CompleteHistory))
+	}
+}
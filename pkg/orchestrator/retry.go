@@ -0,0 +1,148 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// InfraError is a failure of the evaluation infrastructure (Mix, the browser
+// provider or the judge API) rather than of the agent under test. Infra
+// failures are retried; agent failures are judged like any other execution.
+type InfraError struct {
+	Op  string
+	Err error
+	// StepRetried is set when the failing step already used up its own
+	// retries (see retryStep); the task is then recorded instead of rerun
+	StepRetried bool
+}
+
+func (e *InfraError) Error() string {
+	return fmt.Sprintf("%s failed: %v", e.Op, e.Err)
+}
+
+func (e *InfraError) Unwrap() error {
+	return e.Err
+}
+
+// infraError wraps err as an infrastructure failure of the given operation
+func infraError(op string, err error) error {
+	return &InfraError{Op: op, Err: err}
+}
+
+// IsInfraError reports whether err is (or wraps) an infrastructure failure
+func IsInfraError(err error) bool {
+	var infraErr *InfraError
+	return errors.As(err, &infraErr)
+}
+
+// rerunnable reports whether a failed task should be run again from scratch
+func rerunnable(err error) bool {
+	var infraErr *InfraError
+	return errors.As(err, &infraErr) && !infraErr.StepRetried
+}
+
+// runTaskWithRetry runs a task, rerunning it after infrastructure failures
+// with exponential backoff. When the task still errors, it returns a result
+// that records the failure so the task is not silently missing from the run.
+func (o *Orchestrator) runTaskWithRetry(ctx context.Context, task convex.Task, run taskRunner) (*convex.TaskResult, error) {
+	logger := loggerFrom(ctx)
+	for attempt := 1; ; attempt++ {
+		result, err := run(ctx, task)
+		if err == nil {
+			result.Attempts = attempt
+			return result, nil
+		}
+
+		// Cancelled tasks are abandoned, not recorded
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		if !rerunnable(err) || attempt > o.config.InfraRetries {
+			logger.Warn("task failed", "attempts", attempt, "error", err)
+			return errorResult(task, attempt, err), nil
+		}
+
+		backoff := o.retryBackoff(attempt)
+		logger.Warn("infrastructure failure, retrying",
+			"attempt", attempt, "max_attempts", o.config.InfraRetries+1, "backoff", backoff, "error", err)
+		if !sleepCtx(ctx, backoff) {
+			return nil, err
+		}
+	}
+}
+
+// retryStep runs one step of a task, retrying its infrastructure failures in
+// place with the same backoff as whole tasks. It is used for the steps after
+// the agent has run (its message history, the judge), so a hiccup there does
+// not pay for the agent again and replace the execution being judged. The
+// final failure is marked StepRetried so the task is recorded, not rerun.
+func (o *Orchestrator) retryStep(ctx context.Context, op string, step func() error) error {
+	logger := loggerFrom(ctx)
+	for attempt := 1; ; attempt++ {
+		err := step()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+
+		var infraErr *InfraError
+		if !errors.As(err, &infraErr) {
+			return err
+		}
+		if attempt > o.config.InfraRetries {
+			infraErr.StepRetried = true
+			return err
+		}
+
+		backoff := o.retryBackoff(attempt)
+		logger.Warn("infrastructure failure, retrying step", "step", op,
+			"attempt", attempt, "max_attempts", o.config.InfraRetries+1, "backoff", backoff, "error", err)
+		if !sleepCtx(ctx, backoff) {
+			return err
+		}
+	}
+}
+
+// retryBackoff is the delay after the given failed attempt, doubling each time
+func (o *Orchestrator) retryBackoff(attempt int) time.Duration {
+	return o.config.RetryBackoff << (attempt - 1)
+}
+
+// sleepCtx waits for d, returning false if ctx ends first
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// errorResult builds the result saved for a task that could not be
+// evaluated. Agent failures are judged, so a task only errors when the
+// infrastructure around it fails.
+func errorResult(task convex.Task, attempts int, err error) *convex.TaskResult {
+	return &convex.TaskResult{
+		RunID:      task.RunID,
+		TaskID:     task.ID,
+		Trial:      task.Trial,
+		Task:       task.Text,
+		Attempts:   attempts,
+		InfraError: err.Error(),
+		Evaluation: &convex.Evaluation{
+			Passed:    false,
+			Score:     0.0,
+			Reasoning: fmt.Sprintf("Task could not be evaluated after %d attempt(s): %v", attempts, err),
+			Errors:    []string{convex.ErrorCategoryInfra},
+		},
+	}
+}
@@ -0,0 +1,144 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// scriptedRunner fails with the scripted errors in turn, then succeeds
+type scriptedRunner struct {
+	errs  []error
+	calls []time.Time
+}
+
+func (s *scriptedRunner) run(ctx context.Context, task convex.Task) (*convex.TaskResult, error) {
+	s.calls = append(s.calls, time.Now())
+	if i := len(s.calls) - 1; i < len(s.errs) {
+		return nil, s.errs[i]
+	}
+	return &convex.TaskResult{RunID: task.RunID, TaskID: task.ID, Evaluation: &convex.Evaluation{Passed: true, Score: 1}}, nil
+}
+
+func TestRunTaskWithRetry(t *testing.T) {
+	mixDown := infraError("session creation", errors.New("connection refused"))
+	judgeDown := func() error {
+		return &InfraError{Op: "evaluation", Err: errors.New("judge API call failed"), StepRetried: true}
+	}
+
+	tests := []struct {
+		name         string
+		errs         []error
+		wantCalls    int
+		wantAttempts int
+		wantInfra    bool
+	}{
+		{"first attempt passes", nil, 1, 1, false},
+		{"infra failure is rerun", []error{mixDown}, 2, 2, false},
+		{"infra failures exhaust retries", []error{mixDown, mixDown, mixDown}, 3, 3, true},
+		{"step-retried failure is not rerun", []error{judgeDown()}, 1, 1, true},
+		{"unexpected error is not rerun", []error{errors.New("boom")}, 1, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Orchestrator{config: Config{InfraRetries: 2, RetryBackoff: time.Millisecond}}
+			runner := &scriptedRunner{errs: tt.errs}
+			task := convex.Task{ID: "task-1", RunID: "run-1", Trial: 2, Text: "Find the title"}
+
+			result, err := o.runTaskWithRetry(context.Background(), task, runner.run)
+			if err != nil {
+				t.Fatalf("runTaskWithRetry failed: %v", err)
+			}
+			if len(runner.calls) != tt.wantCalls {
+				t.Errorf("Expected %d runs, got %d", tt.wantCalls, len(runner.calls))
+			}
+			if result.Attempts != tt.wantAttempts {
+				t.Errorf("Expected %d attempts recorded, got %d", tt.wantAttempts, result.Attempts)
+			}
+			if (result.InfraError != "") != tt.wantInfra {
+				t.Errorf("Expected infra error recorded: %v, got %q", tt.wantInfra, result.InfraError)
+			}
+			if tt.wantInfra {
+				if result.TaskID != "task-1" || result.Trial != 2 || result.Evaluation == nil || result.Evaluation.Passed {
+					t.Errorf("Unexpected error result: %+v", result)
+				}
+				if errs := result.Evaluation.Errors; len(errs) != 1 || errs[0] != convex.ErrorCategoryInfra {
+					t.Errorf("Expected the infra_error category, got %v", errs)
+				}
+			}
+		})
+	}
+}
+
+func TestRunTaskWithRetryBacksOff(t *testing.T) {
+	failure := infraError("send message", errors.New("503"))
+	o := &Orchestrator{config: Config{InfraRetries: 2, RetryBackoff: 20 * time.Millisecond}}
+	runner := &scriptedRunner{errs: []error{failure, failure}}
+
+	if _, err := o.runTaskWithRetry(context.Background(), convex.Task{ID: "task-1"}, runner.run); err != nil {
+		t.Fatalf("runTaskWithRetry failed: %v", err)
+	}
+	if len(runner.calls) != 3 {
+		t.Fatalf("Expected 3 runs, got %d", len(runner.calls))
+	}
+	for i, want := range []time.Duration{20 * time.Millisecond, 40 * time.Millisecond} {
+		if gap := runner.calls[i+1].Sub(runner.calls[i]); gap < want {
+			t.Errorf("Retry %d: expected a backoff of at least %s, got %s", i+1, want, gap)
+		}
+	}
+}
+
+func TestRunTaskWithRetryCancelledDuringBackoff(t *testing.T) {
+	o := &Orchestrator{config: Config{InfraRetries: 2, RetryBackoff: time.Hour}}
+	runner := &scriptedRunner{errs: []error{infraError("session creation", errors.New("refused"))}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result, err := o.runTaskWithRetry(ctx, convex.Task{ID: "task-1"}, runner.run)
+	if err == nil || result != nil {
+		t.Errorf("Expected a cancelled task to be abandoned, got result %+v, error %v", result, err)
+	}
+	if len(runner.calls) != 1 {
+		t.Errorf("Expected no rerun after cancellation, got %d runs", len(runner.calls))
+	}
+}
+
+func TestRetryStep(t *testing.T) {
+	o := &Orchestrator{config: Config{InfraRetries: 2, RetryBackoff: time.Millisecond}}
+
+	calls := 0
+	err := o.retryStep(context.Background(), "evaluation", func() error {
+		if calls++; calls < 3 {
+			return infraError("evaluation", errors.New("judge API call failed"))
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Expected the step to succeed on its third attempt, got %d attempts, error %v", calls, err)
+	}
+
+	calls = 0
+	err = o.retryStep(context.Background(), "evaluation", func() error {
+		calls++
+		return infraError("evaluation", errors.New("judge API call failed"))
+	})
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+	if err == nil || rerunnable(err) {
+		t.Errorf("Expected an exhausted step to fail without rerunning the task, got %v", err)
+	}
+
+	calls = 0
+	err = o.retryStep(context.Background(), "evaluation", func() error {
+		calls++
+		return errors.New("invalid verdict")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("Expected other errors to be returned without retrying, got %d attempts, error %v", calls, err)
+	}
+}
@@ -0,0 +1,142 @@
+package orchestrator
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// blockingRunner holds every task until released or cancelled
+type blockingRunner struct {
+	started chan string
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func newBlockingRunner() *blockingRunner {
+	return &blockingRunner{started: make(chan string, 16), release: make(chan struct{})}
+}
+
+func (r *blockingRunner) run(ctx context.Context, task convex.Task) (*convex.TaskResult, error) {
+	r.calls.Add(1)
+	r.started <- task.ID
+	select {
+	case <-r.release:
+		return &convex.TaskResult{RunID: task.RunID, TaskID: task.ID, Evaluation: &convex.Evaluation{Passed: true, Score: 1}}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// waitStarted waits for n tasks to start
+func (r *blockingRunner) waitStarted(t *testing.T, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d tasks to start, got %d", n, i)
+		}
+	}
+}
+
+func drainTasks() []convex.Task {
+	var tasks []convex.Task
+	for _, id := range []string{"task-1", "task-2", "task-3", "task-4"} {
+		tasks = append(tasks, convex.Task{ID: id, RunID: "run-1"})
+	}
+	return tasks
+}
+
+func TestRunTasksDrainsInFlightTasksOnShutdown(t *testing.T) {
+	sink := &memorySink{}
+	o := &Orchestrator{config: Config{ShutdownGrace: time.Hour}, sink: sink, logger: slog.Default()}
+	runner := newBlockingRunner()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	type runResult struct {
+		summary *RunSummary
+		err     error
+	}
+	finished := make(chan runResult, 1)
+	go func() {
+		summary, err := o.runTasks(ctx, &runPlan{tasks: drainTasks()}, 2, 1, runner.run)
+		finished <- runResult{summary, err}
+	}()
+
+	runner.waitStarted(t, 2)
+	cancel()
+	close(runner.release)
+
+	var got runResult
+	select {
+	case got = <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the run to finish once in-flight tasks drained")
+	}
+
+	if got.err == nil {
+		t.Error("Expected an interrupted run to return an error")
+	}
+	if calls := runner.calls.Load(); calls != 2 {
+		t.Errorf("Expected no new tasks after shutdown, got %d started", calls)
+	}
+	if saved := sink.saved(); len(saved) != 2 {
+		t.Errorf("Expected both in-flight results to be saved, got %d", len(saved))
+	}
+	s := got.summary
+	if !s.Interrupted || s.Total != 4 || s.Saved != 2 || s.Abandoned != 2 {
+		t.Errorf("Unexpected summary: interrupted=%v total=%d saved=%d abandoned=%d", s.Interrupted, s.Total, s.Saved, s.Abandoned)
+	}
+}
+
+func TestRunTasksCancelsInFlightTasks(t *testing.T) {
+	tests := []struct {
+		name  string
+		grace time.Duration
+		stop  func(o *Orchestrator, cancel context.CancelFunc)
+	}{
+		{"grace period expires", 20 * time.Millisecond, func(_ *Orchestrator, cancel context.CancelFunc) { cancel() }},
+		{"abort", time.Hour, func(o *Orchestrator, cancel context.CancelFunc) { cancel(); o.Abort() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &memorySink{}
+			o := &Orchestrator{config: Config{ShutdownGrace: tt.grace}, sink: sink, logger: slog.Default()}
+			runner := newBlockingRunner()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			finished := make(chan *RunSummary, 1)
+			go func() {
+				summary, _ := o.runTasks(ctx, &runPlan{tasks: drainTasks()}, 2, 1, runner.run)
+				finished <- summary
+			}()
+
+			runner.waitStarted(t, 2)
+			tt.stop(o, cancel)
+
+			var summary *RunSummary
+			select {
+			case summary = <-finished:
+			case <-time.After(5 * time.Second):
+				t.Fatal("Expected in-flight tasks to be cancelled")
+			}
+
+			if calls := runner.calls.Load(); calls != 2 {
+				t.Errorf("Expected no new tasks after shutdown, got %d started", calls)
+			}
+			if saved := sink.saved(); len(saved) != 0 {
+				t.Errorf("Expected cancelled tasks to be abandoned, got %d saved", len(saved))
+			}
+			if !summary.Interrupted || summary.Saved != 0 || summary.Total != 4 {
+				t.Errorf("Unexpected summary: interrupted=%v total=%d saved=%d", summary.Interrupted, summary.Total, summary.Saved)
+			}
+		})
+	}
+}
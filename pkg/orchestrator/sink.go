@@ -0,0 +1,53 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// ResultSink persists task results produced by a run.
+type ResultSink interface {
+	SaveTaskResult(ctx context.Context, result *convex.TaskResult) error
+}
+
+// ResultSource reads back the results saved for a run
+type ResultSource interface {
+	FetchRunResults(ctx context.Context, runID string) ([]convex.TaskResult, error)
+}
+
+// The Convex client is the default sink.
+var (
+	_ ResultSink   = (*convex.Client)(nil)
+	_ ResultSource = (*convex.Client)(nil)
+)
+
+// MultiSink fans a result out to several sinks.
+type MultiSink []ResultSink
+
+// NewMultiSink creates a sink that writes every result to all given sinks.
+func NewMultiSink(sinks ...ResultSink) MultiSink {
+	return MultiSink(sinks)
+}
+
+// SaveTaskResult writes the result to every sink, even if an earlier one fails.
+func (m MultiSink) SaveTaskResult(ctx context.Context, result *convex.TaskResult) error {
+	var errs []error
+	for _, sink := range m {
+		if err := sink.SaveTaskResult(ctx, result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FetchRunResults reads the results back from the first sink that can.
+func (m MultiSink) FetchRunResults(ctx context.Context, runID string) ([]convex.TaskResult, error) {
+	for _, sink := range m {
+		if source, ok := sink.(ResultSource); ok {
+			return source.FetchRunResults(ctx, runID)
+		}
+	}
+	return nil, errors.New("no sink can read results back")
+}
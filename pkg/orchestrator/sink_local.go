@@ -0,0 +1,108 @@
+package orchestrator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// resultsFileName is the JSONL file each run's results are appended to
+const resultsFileName = "results.jsonl"
+
+// maxResultLine bounds one result read back; an inline message history can
+// make lines far longer than bufio's default
+const maxResultLine = 64 << 20
+
+// LocalSink writes task results to disk as <dir>/<runID>/results.jsonl,
+// one convex.TaskResult per line, so they can be uploaded later.
+type LocalSink struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewLocalSink creates a sink rooted at dir, creating it if needed.
+func NewLocalSink(dir string) (*LocalSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create output dir failed: %w", err)
+	}
+	return &LocalSink{dir: dir}, nil
+}
+
+// SaveTaskResult appends the result to the run's JSONL file.
+func (s *LocalSink) SaveTaskResult(_ context.Context, result *convex.TaskResult) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("marshal task result failed: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dir := runDir(s.dir, result.RunID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create run dir failed: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, resultsFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open results file failed: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write task result failed: %w", err)
+	}
+	return nil
+}
+
+// FetchRunResults reads back the results saved for a run, skipping a line
+// torn by a crash mid-write
+func (s *LocalSink) FetchRunResults(_ context.Context, runID string) ([]convex.TaskResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(filepath.Join(runDir(s.dir, runID), resultsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open results file failed: %w", err)
+	}
+	defer f.Close()
+
+	var results []convex.TaskResult
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, maxResultLine)
+	for scanner.Scan() {
+		var result convex.TaskResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			continue
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read results file failed: %w", err)
+	}
+	return results, nil
+}
+
+// runDir returns the directory holding local artifacts for a run
+func runDir(outputDir, runID string) string {
+	return filepath.Join(outputDir, safePathComponent(runID))
+}
+
+// safePathComponent makes an ID usable as a single path element
+func safePathComponent(id string) string {
+	if id == "" {
+		return "_"
+	}
+	return strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(id)
+}
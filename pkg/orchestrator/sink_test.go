@@ -0,0 +1,107 @@
+package orchestrator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// memorySink records saved results, failing with err when set
+type memorySink struct {
+	mu      sync.Mutex
+	err     error
+	results []*convex.TaskResult
+}
+
+func (s *memorySink) SaveTaskResult(_ context.Context, result *convex.TaskResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.results = append(s.results, result)
+	return nil
+}
+
+func (s *memorySink) saved() []*convex.TaskResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*convex.TaskResult(nil), s.results...)
+}
+
+func TestLocalSinkRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewLocalSink(filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatalf("NewLocalSink failed: %v", err)
+	}
+
+	want := []*convex.TaskResult{
+		{RunID: "run/1", TaskID: "task-1", Trial: 1, Attempts: 1, Evaluation: &convex.Evaluation{Passed: true, Score: 1, Reasoning: "Found it"}},
+		{RunID: "run/1", TaskID: "task-2", Attempts: 3, InfraError: "session creation failed", Evaluation: &convex.Evaluation{Errors: []string{convex.ErrorCategoryInfra}}},
+	}
+	for _, result := range want {
+		if err := sink.SaveTaskResult(context.Background(), result); err != nil {
+			t.Fatalf("SaveTaskResult failed: %v", err)
+		}
+	}
+
+	f, err := os.Open(filepath.Join(dir, "out", "run_1", resultsFileName))
+	if err != nil {
+		t.Fatalf("Expected results under a sanitized run dir: %v", err)
+	}
+	defer f.Close()
+
+	var got []convex.TaskResult
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var result convex.TaskResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("Line %d is not a task result: %v", len(got)+1, err)
+		}
+		got = append(got, result)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Reading results failed: %v", err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d lines, got %d", len(want), len(got))
+	}
+	for i := range want {
+		wantJSON, _ := json.Marshal(want[i])
+		gotJSON, _ := json.Marshal(got[i])
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("Line %d: expected %s, got %s", i+1, wantJSON, gotJSON)
+		}
+	}
+}
+
+func TestMultiSinkKeepsGoingPastFailures(t *testing.T) {
+	convexDown := errors.New("convex unavailable")
+	failing := &memorySink{err: convexDown}
+	first, last := &memorySink{}, &memorySink{}
+	sink := NewMultiSink(first, failing, last)
+
+	result := &convex.TaskResult{RunID: "run-1", TaskID: "task-1"}
+	err := sink.SaveTaskResult(context.Background(), result)
+	if !errors.Is(err, convexDown) {
+		t.Errorf("Expected the failing sink's error, got %v", err)
+	}
+	for name, s := range map[string]*memorySink{"first": first, "last": last} {
+		if saved := s.saved(); len(saved) != 1 || saved[0] != result {
+			t.Errorf("Expected the %s sink to save the result, got %v", name, saved)
+		}
+	}
+
+	if err := NewMultiSink(first, last).SaveTaskResult(context.Background(), result); err != nil {
+		t.Errorf("Expected no error when every sink succeeds, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/recreate-run/mix-go-sdk/models/components"
+
+	"mix-eval-go/pkg/sse"
+)
+
+const (
+	// streamConnectTimeout bounds how long RunTask waits for the stream to connect
+	streamConnectTimeout = 30 * time.Second
+	// maxStreamReconnectAttempts is how many consecutive failed reconnects end the stream
+	maxStreamReconnectAttempts = 5
+	// defaultStreamRetry is the reconnect delay until the server sends a retry field
+	defaultStreamRetry = time.Second
+)
+
+// errStreamClosed reports an event stream that ended before the complete event
+var errStreamClosed = errors.New("stream closed before completion")
+
+// eventStream follows a Mix session's SSE stream (manual HTTP, SDK has bug).
+// When the connection drops it reconnects with Last-Event-ID and drops
+// replayed events it has already delivered.
+type eventStream struct {
+	url    string
+	logger *slog.Logger
+
+	events chan sse.Event
+	ready  chan struct{}
+	done   chan struct{}
+	err    error // set before done is closed
+
+	connected   bool
+	lastEventID string
+	retry       time.Duration
+	seen        map[string]struct{}
+	reconnects  int
+
+	// trace records every event as received, before filtering (nil to skip)
+	trace *traceWriter
+}
+
+func newEventStream(mixURL, sessionID string, logger *slog.Logger) *eventStream {
+	return &eventStream{
+		url:    fmt.Sprintf("%s/stream?sessionId=%s", mixURL, sessionID),
+		logger: logger,
+		events: make(chan sse.Event, 100),
+		ready:  make(chan struct{}),
+		done:   make(chan struct{}),
+		retry:  defaultStreamRetry,
+		seen:   make(map[string]struct{}),
+	}
+}
+
+// run follows the stream until the complete event has been delivered, then
+// closes events and done. Reconnects and dropped events are handled here.
+func (s *eventStream) run(ctx context.Context) {
+	defer close(s.done)
+	defer close(s.events)
+
+	for failures := 0; ; {
+		connected, err := s.follow(ctx)
+		if err == nil {
+			return
+		}
+		if ctx.Err() != nil {
+			s.err = ctx.Err()
+			return
+		}
+		if connected {
+			failures = 0
+		}
+		failures++
+		if failures > maxStreamReconnectAttempts {
+			s.err = fmt.Errorf("stream reconnect failed after %d attempts: %w", maxStreamReconnectAttempts, err)
+			return
+		}
+
+		s.logger.Warn("event stream dropped, reconnecting",
+			"attempt", failures, "last_event_id", s.lastEventID, "retry", s.retry, "error", err)
+		timer := time.NewTimer(s.retry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.err = ctx.Err()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// follow reads one connection of the stream. It reports whether the
+// connection was established and returns nil once the complete event is delivered.
+func (s *eventStream) follow(ctx context.Context) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", s.url, nil)
+	if err != nil {
+		return false, fmt.Errorf("stream request creation failed: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if s.lastEventID != "" {
+		req.Header.Set("Last-Event-ID", s.lastEventID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("stream request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("stream returned status %d", resp.StatusCode)
+	}
+	if s.connected {
+		s.reconnects++
+		s.logger.Info("event stream reconnected", "last_event_id", s.lastEventID, "reconnects", s.reconnects)
+	}
+	s.connected = true
+
+	decoder := sse.NewDecoder(resp.Body)
+	defer func() {
+		if retry := decoder.Retry(); retry > 0 {
+			s.retry = retry
+		}
+	}()
+
+	prevID := ""
+	for {
+		event, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			return true, errStreamClosed
+		}
+		if err != nil {
+			return true, fmt.Errorf("stream read failed: %w", err)
+		}
+		s.trace.event(event, time.Now())
+
+		// A new id on this connection marks a new event, or one replayed after a reconnect
+		if event.ID != prevID {
+			prevID = event.ID
+			if event.ID != "" {
+				if _, dup := s.seen[event.ID]; dup {
+					continue
+				}
+				s.seen[event.ID] = struct{}{}
+				s.lastEventID = event.ID
+			}
+		}
+
+		complete, err := s.dispatch(ctx, event)
+		if err != nil || complete {
+			return true, err
+		}
+	}
+}
+
+// dispatch delivers an event to the events channel, skipping stream
+// housekeeping. It reports whether it was the complete event.
+func (s *eventStream) dispatch(ctx context.Context, event sse.Event) (bool, error) {
+	eventType := mixEventType(event)
+	switch eventType {
+	case string(components.SSEEventStreamTypeConnected):
+		s.markReady()
+		return false, nil
+	case string(components.SSEEventStreamTypeHeartbeat):
+		return false, nil
+	}
+	s.markReady()
+
+	select {
+	case s.events <- event:
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+	return eventType == string(components.SSEEventStreamTypeComplete), nil
+}
+
+// mixEventType returns an event's Mix type: the event field, falling back to
+// the type in its JSON data for events sent without one
+func mixEventType(event sse.Event) string {
+	if event.Type != sse.DefaultEventType {
+		return event.Type
+	}
+	var data struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal([]byte(event.Data), &data); err != nil {
+		return ""
+	}
+	return data.Type
+}
+
+// markReady signals that the stream is connected and receiving events
+func (s *eventStream) markReady() {
+	select {
+	case <-s.ready:
+	default:
+		close(s.ready)
+	}
+}
+
+// waitReady blocks until the stream is connected, it ends, or timeout expires
+func (s *eventStream) waitReady(timeout time.Duration) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-s.ready:
+		return nil
+	case <-s.done:
+		select {
+		case <-s.ready:
+			return nil
+		default:
+		}
+		if s.err != nil {
+			return s.err
+		}
+		return errStreamClosed
+	case <-timer.C:
+		return fmt.Errorf("stream not connected after %s", timeout)
+	}
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventStreamReconnectsAndDeduplicates(t *testing.T) {
+	var mu sync.Mutex
+	var lastEventIDs []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		connection := len(lastEventIDs)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if connection == 1 {
+			// First connection drops after two events
+			fmt.Fprint(w, "retry: 1\n\n")
+			fmt.Fprint(w, "event: connected\nid: 1\ndata: {\"sessionId\":\"s\"}\n\n")
+			fmt.Fprint(w, "event: thinking\nid: 2\ndata: {\"type\":\"thinking\",\"content\":\"a\"}\n\n")
+			fmt.Fprint(w, "event: content\nid: 3\ndata: {\"type\":\"content\",\"content\":\"b\"}\n\n")
+			return
+		}
+		// Reconnect replays event 3 before continuing
+		fmt.Fprint(w, "event: content\nid: 3\ndata: {\"type\":\"content\",\"content\":\"b\"}\n\n")
+		fmt.Fprint(w, "event: heartbeat\nid: 4\ndata: {\"type\":\"heartbeat\"}\n\n")
+		fmt.Fprint(w, "event: complete\nid: 5\ndata: {\"type\":\"complete\"}\n\n")
+	}))
+	defer server.Close()
+
+	stream := newEventStream(server.URL, "s", slog.Default())
+	go stream.run(t.Context())
+
+	if err := stream.waitReady(5 * time.Second); err != nil {
+		t.Fatalf("waitReady failed: %v", err)
+	}
+
+	var types []string
+	for event := range stream.events {
+		types = append(types, mixEventType(event))
+	}
+	<-stream.done
+
+	if stream.err != nil {
+		t.Fatalf("Stream failed: %v", stream.err)
+	}
+	want := []string{"thinking", "content", "complete"}
+	if fmt.Sprint(types) != fmt.Sprint(want) {
+		t.Errorf("Expected events %v, got %v", want, types)
+	}
+	if stream.reconnects != 1 {
+		t.Errorf("Expected 1 reconnect, got %d", stream.reconnects)
+	}
+	if len(lastEventIDs) != 2 || lastEventIDs[1] != "3" {
+		t.Errorf("Expected reconnect with Last-Event-ID 3, got %q", lastEventIDs)
+	}
+}
+
+func TestEventStreamGivesUpAfterFailedReconnects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	stream := newEventStream(server.URL, "s", slog.Default())
+	stream.retry = time.Millisecond
+	go stream.run(t.Context())
+
+	if err := stream.waitReady(5 * time.Second); err == nil {
+		t.Fatal("Expected waitReady to fail for an unavailable stream")
+	}
+	if stream.connected {
+		t.Error("Stream should never have connected")
+	}
+}
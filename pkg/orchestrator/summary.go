@@ -0,0 +1,441 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// summaryFileName is the JSON summary written into each run directory
+const summaryFileName = "summary.json"
+
+// RunSummary is the outcome of a RunMultipleTasks call.
+//
+// Every scheduled task is exactly one of Passed, Failed (judge verdict false),
+// Errored (no verdict: infra/agent error, cancelled or unsaved) or Abandoned
+// (never started because of a shutdown). TimedOut, Impossible, Captcha and
+// NeedsReview count judged tasks carrying those flags and overlap with
+// Passed/Failed.
+type RunSummary struct {
+	RunID       string        `json:"runId"`
+	SourceRunID string        `json:"sourceRunId,omitempty"` // set on rejudged runs
+	StartedAt   time.Time     `json:"startedAt"`
+	FinishedAt  time.Time     `json:"finishedAt"`
+	Interrupted bool          `json:"interrupted"`
+	Total       int           `json:"total"`
+	Saved       int           `json:"saved"`
+	Passed      int           `json:"passed"`
+	Failed      int           `json:"failed"`
+	Errored     int           `json:"errored"`
+	Abandoned   int           `json:"abandoned"`
+	TimedOut    int           `json:"timedOut"`
+	Impossible  int           `json:"impossible"`
+	Captcha     int           `json:"captcha"`
+	NeedsReview int           `json:"needsHumanReview"`
+	PassRate    float64       `json:"passRate"`
+	Durations   DurationStats `json:"durations"`
+	// AgentDurations, JudgeDurations and FirstAction cover tasks that reached the judge
+	AgentDurations DurationStats     `json:"agentDurations"`
+	JudgeDurations DurationStats     `json:"judgeDurations"`
+	FirstAction    DurationStats     `json:"timeToFirstAction"`
+	Tools          []ToolTiming      `json:"tools,omitempty"`
+	AgentUsage     convex.TokenUsage `json:"agentUsage"`
+	JudgeUsage     convex.TokenUsage `json:"judgeUsage"`
+	CostUSD        float64           `json:"costUsd"`
+	Categories     []CategorySummary `json:"categories"`
+	Providers      []ProviderSummary `json:"browserProviders,omitempty"`
+	TrialsK        int               `json:"trialsPerTask,omitempty"`
+	Trials         []TrialStats      `json:"trials,omitempty"`
+	// Resumed counts the tasks of a resumed run whose results were saved by
+	// an earlier attempt; they are read back and counted like the others
+	Resumed int `json:"resumed,omitempty"`
+	// Partial is set when some of those results could not be read back, so
+	// the summary covers only part of the run
+	Partial bool `json:"partial,omitempty"`
+}
+
+// DurationStats are wall-clock percentiles over tasks that produced a result
+type DurationStats struct {
+	P50Ms int64 `json:"p50Ms"`
+	P90Ms int64 `json:"p90Ms"`
+	P99Ms int64 `json:"p99Ms"`
+	MaxMs int64 `json:"maxMs"`
+}
+
+// CategorySummary breaks the run down by convex.Task.Category
+type CategorySummary struct {
+	Category string  `json:"category"`
+	Total    int     `json:"total"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	Errored  int     `json:"errored"`
+	TimedOut int     `json:"timedOut"`
+	PassRate float64 `json:"passRate"`
+}
+
+// ToolTiming is the latency of one tool across the run
+type ToolTiming struct {
+	Tool   string `json:"tool"`
+	Calls  int    `json:"calls"`
+	Errors int    `json:"errors"`
+	DurationStats
+}
+
+// ProviderSummary breaks the run down by the browser provider tasks ran on
+// (convex.TaskResult.BrowserProvider, which failover may change per task)
+type ProviderSummary struct {
+	Provider string  `json:"provider"`
+	Total    int     `json:"total"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	Errored  int     `json:"errored"`
+	PassRate float64 `json:"passRate"`
+}
+
+// taskOutcome records what happened to one scheduled task
+type taskOutcome struct {
+	task     convex.Task
+	result   *convex.TaskResult // nil when the task was cancelled
+	saved    bool
+	duration time.Duration
+}
+
+// outcomeLog collects task outcomes across parallel workers
+type outcomeLog struct {
+	mu       sync.Mutex
+	outcomes []taskOutcome
+}
+
+func (l *outcomeLog) add(outcome taskOutcome) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.outcomes = append(l.outcomes, outcome)
+}
+
+// outcomeKind classifies a task outcome into a single summary bucket
+type outcomeKind int
+
+const (
+	outcomePassed outcomeKind = iota
+	outcomeFailed
+	outcomeErrored
+)
+
+func classifyOutcome(o taskOutcome) outcomeKind {
+	if o.result == nil || !o.saved || o.result.Evaluation == nil {
+		return outcomeErrored
+	}
+	if slices.Contains(o.result.Evaluation.Errors, convex.ErrorCategoryInfra) {
+		return outcomeErrored
+	}
+	if o.result.Evaluation.Passed {
+		return outcomePassed
+	}
+	return outcomeFailed
+}
+
+// summarize builds the run summary from collected outcomes
+func summarize(runID string, outcomes []taskOutcome, abandoned, trials int) *RunSummary {
+	summary := &RunSummary{
+		RunID:     runID,
+		Total:     len(outcomes) + abandoned,
+		Abandoned: abandoned,
+	}
+
+	categories := make(map[string]*CategorySummary)
+	browserProviders := make(map[string]*ProviderSummary)
+	var durations, agentDurations, judgeDurations, firstActions []time.Duration
+	tools := make(map[string]*ToolTiming)
+	toolDurations := make(map[string][]time.Duration)
+	var results []*convex.TaskResult
+
+	for _, o := range outcomes {
+		category := o.task.Category
+		if category == "" {
+			category = "uncategorized"
+		}
+		cat, ok := categories[category]
+		if !ok {
+			cat = &CategorySummary{Category: category}
+			categories[category] = cat
+		}
+		cat.Total++
+
+		if o.saved {
+			summary.Saved++
+		}
+
+		var provider *ProviderSummary
+		if o.result != nil && o.result.BrowserProvider != "" {
+			name := o.result.BrowserProvider
+			provider, ok = browserProviders[name]
+			if !ok {
+				provider = &ProviderSummary{Provider: name}
+				browserProviders[name] = provider
+			}
+			provider.Total++
+		}
+
+		kind := classifyOutcome(o)
+		switch kind {
+		case outcomePassed:
+			summary.Passed++
+			cat.Passed++
+		case outcomeFailed:
+			summary.Failed++
+			cat.Failed++
+		case outcomeErrored:
+			summary.Errored++
+			cat.Errored++
+		}
+		if provider != nil {
+			switch kind {
+			case outcomePassed:
+				provider.Passed++
+			case outcomeFailed:
+				provider.Failed++
+			case outcomeErrored:
+				provider.Errored++
+			}
+		}
+
+		if o.result == nil {
+			continue
+		}
+		results = append(results, o.result)
+		durations = append(durations, o.duration)
+		if o.result.AgentDurationMs > 0 {
+			agentDurations = append(agentDurations, time.Duration(o.result.AgentDurationMs)*time.Millisecond)
+			judgeDurations = append(judgeDurations, time.Duration(o.result.JudgeDurationMs)*time.Millisecond)
+		}
+		if o.result.TimeToFirstActionMs > 0 {
+			firstActions = append(firstActions, time.Duration(o.result.TimeToFirstActionMs)*time.Millisecond)
+		}
+		for _, tc := range o.result.ToolCalls {
+			tool, ok := tools[tc.ToolName]
+			if !ok {
+				tool = &ToolTiming{Tool: tc.ToolName}
+				tools[tc.ToolName] = tool
+			}
+			tool.Calls++
+			if tc.IsError {
+				tool.Errors++
+			}
+			if tc.StartedAt > 0 {
+				toolDurations[tc.ToolName] = append(toolDurations[tc.ToolName], time.Duration(tc.DurationMs)*time.Millisecond)
+			}
+		}
+		if o.result.AgentUsage != nil {
+			summary.AgentUsage.Add(*o.result.AgentUsage)
+		}
+		if o.result.JudgeUsage != nil {
+			summary.JudgeUsage.Add(*o.result.JudgeUsage)
+		}
+		if eval := o.result.Evaluation; eval != nil {
+			if slices.Contains(eval.Errors, convex.ErrorCategoryTimeout) {
+				summary.TimedOut++
+				cat.TimedOut++
+			}
+			if eval.ImpossibleTask {
+				summary.Impossible++
+			}
+			if eval.ReachedCaptcha {
+				summary.Captcha++
+			}
+			if eval.NeedsHumanReview {
+				summary.NeedsReview++
+			}
+		}
+	}
+
+	summary.PassRate = passRate(summary.Passed, summary.Passed+summary.Failed+summary.Errored)
+	summary.Durations = durationStats(durations)
+	summary.AgentDurations = durationStats(agentDurations)
+	summary.JudgeDurations = durationStats(judgeDurations)
+	summary.FirstAction = durationStats(firstActions)
+	for name, tool := range tools {
+		tool.DurationStats = durationStats(toolDurations[name])
+		summary.Tools = append(summary.Tools, *tool)
+	}
+	sort.Slice(summary.Tools, func(i, j int) bool {
+		return summary.Tools[i].Tool < summary.Tools[j].Tool
+	})
+	summary.CostUSD = summary.AgentUsage.CostUSD + summary.JudgeUsage.CostUSD
+
+	for _, cat := range categories {
+		cat.PassRate = passRate(cat.Passed, cat.Total)
+		summary.Categories = append(summary.Categories, *cat)
+	}
+	sort.Slice(summary.Categories, func(i, j int) bool {
+		return summary.Categories[i].Category < summary.Categories[j].Category
+	})
+
+	for _, provider := range browserProviders {
+		provider.PassRate = passRate(provider.Passed, provider.Total)
+		summary.Providers = append(summary.Providers, *provider)
+	}
+	sort.Slice(summary.Providers, func(i, j int) bool {
+		return summary.Providers[i].Provider < summary.Providers[j].Provider
+	})
+
+	if trials > 1 {
+		summary.TrialsK = trials
+		summary.Trials = AggregateTrials(results, trials)
+	}
+	return summary
+}
+
+func passRate(passed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(passed) / float64(total)
+}
+
+// durationStats computes nearest-rank percentiles
+func durationStats(durations []time.Duration) DurationStats {
+	if len(durations) == 0 {
+		return DurationStats{}
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	return DurationStats{
+		P50Ms: percentile(sorted, 50).Milliseconds(),
+		P90Ms: percentile(sorted, 90).Milliseconds(),
+		P99Ms: percentile(sorted, 99).Milliseconds(),
+		MaxMs: sorted[len(sorted)-1].Milliseconds(),
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// AllErrored reports whether no task in the run produced a judge verdict
+func (s *RunSummary) AllErrored() bool {
+	return s.Passed+s.Failed == 0
+}
+
+// WriteTable renders the summary as human-readable tables
+func (s *RunSummary) WriteTable(w io.Writer) {
+	fmt.Fprintf(w, "\nRun summary: %s (%s)\n", s.RunID, s.FinishedAt.Sub(s.StartedAt).Round(time.Second))
+	if s.SourceRunID != "" {
+		fmt.Fprintf(w, "Rejudged from: %s\n", s.SourceRunID)
+	}
+	if s.Resumed > 0 {
+		fmt.Fprintf(w, "Resumed: %d tasks saved by earlier attempts\n", s.Resumed)
+	}
+	if s.Partial {
+		fmt.Fprintln(w, "Partial: some results saved by earlier attempts could not be read back")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOTAL\tPASSED\tFAILED\tERRORED\tABANDONED\tTIMED OUT\tIMPOSSIBLE\tCAPTCHA\tPASS RATE")
+	fmt.Fprintf(tw, "%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%.1f%%\n",
+		s.Total, s.Passed, s.Failed, s.Errored, s.Abandoned, s.TimedOut, s.Impossible, s.Captcha, s.PassRate*100)
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	printDurations(w, "Task duration", s.Durations)
+	printDurations(w, "Agent time", s.AgentDurations)
+	printDurations(w, "Judge time", s.JudgeDurations)
+	printDurations(w, "First action", s.FirstAction)
+	if s.NeedsReview > 0 {
+		fmt.Fprintf(w, "Needs human review: %d (judges disagreed)\n", s.NeedsReview)
+	}
+
+	if s.CostUSD > 0 || s.AgentUsage.InputTokens > 0 || s.JudgeUsage.Calls > 0 {
+		fmt.Fprintf(w, "Cost: %s (agent %s, %d in / %d out tokens; judge %s, %d in / %d out tokens over %d calls)\n",
+			formatCost(s.CostUSD, s.AgentUsage.Unpriced || s.JudgeUsage.Unpriced),
+			formatCost(s.AgentUsage.CostUSD, s.AgentUsage.Unpriced), s.AgentUsage.InputTokens, s.AgentUsage.OutputTokens,
+			formatCost(s.JudgeUsage.CostUSD, s.JudgeUsage.Unpriced), s.JudgeUsage.InputTokens, s.JudgeUsage.OutputTokens, s.JudgeUsage.Calls)
+	}
+
+	if len(s.Categories) > 0 {
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "CATEGORY\tTOTAL\tPASSED\tFAILED\tERRORED\tTIMED OUT\tPASS RATE")
+		for _, c := range s.Categories {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%.1f%%\n",
+				c.Category, c.Total, c.Passed, c.Failed, c.Errored, c.TimedOut, c.PassRate*100)
+		}
+		tw.Flush()
+	}
+
+	if len(s.Tools) > 0 {
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "TOOL\tCALLS\tERRORS\tP50\tP90\tP99\tMAX")
+		for _, tool := range s.Tools {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n",
+				tool.Tool, tool.Calls, tool.Errors, msDuration(tool.P50Ms), msDuration(tool.P90Ms),
+				msDuration(tool.P99Ms), msDuration(tool.MaxMs))
+		}
+		tw.Flush()
+	}
+
+	if len(s.Providers) > 0 {
+		fmt.Fprintln(w)
+		tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "BROWSER PROVIDER\tTOTAL\tPASSED\tFAILED\tERRORED\tPASS RATE")
+		for _, p := range s.Providers {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%.1f%%\n",
+				p.Provider, p.Total, p.Passed, p.Failed, p.Errored, p.PassRate*100)
+		}
+		tw.Flush()
+	}
+
+	if len(s.Trials) > 0 {
+		fmt.Fprintln(w)
+		printTrialStats(w, s.Trials, s.TrialsK)
+	}
+}
+
+// WriteJSON writes the summary to path, creating parent directories
+func (s *RunSummary) WriteJSON(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create summary dir failed: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal summary failed: %w", err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// SummaryPath returns the default location of a run's JSON summary
+func SummaryPath(outputDir, runID string) string {
+	return filepath.Join(runDir(outputDir, runID), summaryFileName)
+}
+
+// printDurations writes one line of duration percentiles
+func printDurations(w io.Writer, label string, d DurationStats) {
+	fmt.Fprintf(w, "%s: p50=%s p90=%s p99=%s max=%s\n", label,
+		msDuration(d.P50Ms), msDuration(d.P90Ms), msDuration(d.P99Ms), msDuration(d.MaxMs))
+}
+
+// formatCost renders a USD amount, marking lower bounds from unpriced models
+func formatCost(usd float64, unpriced bool) string {
+	if unpriced {
+		return fmt.Sprintf(">=$%.4f", usd)
+	}
+	return fmt.Sprintf("$%.4f", usd)
+}
+
+func msDuration(ms int64) time.Duration {
+	return (time.Duration(ms) * time.Millisecond).Round(time.Second / 10)
+}
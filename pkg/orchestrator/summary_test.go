@@ -0,0 +1,149 @@
+package orchestrator
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+)
+
+func TestSummarize(t *testing.T) {
+	outcome := func(category string, eval *convex.Evaluation, saved bool, seconds int) taskOutcome {
+		o := taskOutcome{
+			task:     convex.Task{ID: category + "-task", Category: category},
+			saved:    saved,
+			duration: time.Duration(seconds) * time.Second,
+		}
+		if eval != nil {
+			o.result = &convex.TaskResult{TaskID: o.task.ID, Evaluation: eval}
+		}
+		return o
+	}
+	outcomes := []taskOutcome{
+		outcome("Search", &convex.Evaluation{Passed: true, Score: 1, NeedsHumanReview: true}, true, 10),
+		outcome("Search", &convex.Evaluation{Passed: false, Errors: []string{"task_incomplete", convex.ErrorCategoryTimeout}}, true, 40),
+		outcome("Scraping", &convex.Evaluation{Passed: false, ImpossibleTask: true, ReachedCaptcha: true}, true, 20),
+		outcome("Scraping", &convex.Evaluation{Errors: []string{convex.ErrorCategoryInfra}}, true, 30),
+		outcome("Scraping", nil, false, 5), // cancelled mid-task
+	}
+
+	s := summarize("run-1", outcomes, 2, 1)
+
+	checks := []struct {
+		name      string
+		got, want int
+	}{
+		{"total", s.Total, 7},
+		{"saved", s.Saved, 4},
+		{"passed", s.Passed, 1},
+		{"failed", s.Failed, 2},
+		{"errored", s.Errored, 2},
+		{"abandoned", s.Abandoned, 2},
+		{"timed out", s.TimedOut, 1},
+		{"impossible", s.Impossible, 1},
+		{"captcha", s.Captcha, 1},
+		{"needs review", s.NeedsReview, 1},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("Expected %s=%d, got %d", c.name, c.want, c.got)
+		}
+	}
+
+	if s.Durations.P50Ms != 20000 || s.Durations.MaxMs != 40000 {
+		t.Errorf("Unexpected duration stats: %+v", s.Durations)
+	}
+
+	if len(s.Categories) != 2 || s.Categories[0].Category != "Scraping" {
+		t.Fatalf("Unexpected categories: %+v", s.Categories)
+	}
+	if s.Categories[0].Errored != 2 || s.Categories[1].Passed != 1 {
+		t.Errorf("Unexpected category counts: %+v", s.Categories)
+	}
+	if len(s.Providers) != 0 {
+		t.Errorf("Expected no provider breakdown without browser providers, got %+v", s.Providers)
+	}
+}
+
+func TestSummarizeByBrowserProvider(t *testing.T) {
+	outcome := func(provider string, passed bool) taskOutcome {
+		return taskOutcome{
+			saved: true,
+			result: &convex.TaskResult{
+				BrowserProvider: provider,
+				Evaluation:      &convex.Evaluation{Passed: passed},
+			},
+		}
+	}
+	s := summarize("run-1", []taskOutcome{
+		outcome("browserbase", true),
+		outcome("browserbase", false),
+		outcome("hyperbrowser", true),
+	}, 0, 1)
+
+	if len(s.Providers) != 2 || s.Providers[0].Provider != "browserbase" {
+		t.Fatalf("Unexpected providers: %+v", s.Providers)
+	}
+	if s.Providers[0].PassRate != 0.5 || s.Providers[1].Passed != 1 {
+		t.Errorf("Unexpected provider counts: %+v", s.Providers)
+	}
+}
+
+func TestSummarizeUsage(t *testing.T) {
+	outcome := func(agentCost, judgeCost float64, unpriced bool) taskOutcome {
+		return taskOutcome{
+			saved: true,
+			result: &convex.TaskResult{
+				Evaluation: &convex.Evaluation{Passed: true},
+				AgentUsage: &convex.TokenUsage{InputTokens: 1000, OutputTokens: 100, CostUSD: agentCost},
+				JudgeUsage: &convex.TokenUsage{Calls: 2, InputTokens: 500, OutputTokens: 50, CostUSD: judgeCost, Unpriced: unpriced},
+			},
+		}
+	}
+	s := summarize("run-1", []taskOutcome{
+		outcome(0.10, 0.01, false),
+		outcome(0.20, 0.02, true),
+		{task: convex.Task{ID: "cancelled"}},
+	}, 0, 1)
+
+	if s.AgentUsage.InputTokens != 2000 || s.JudgeUsage.Calls != 4 || !s.JudgeUsage.Unpriced {
+		t.Errorf("Unexpected usage totals: agent %+v judge %+v", s.AgentUsage, s.JudgeUsage)
+	}
+	if math.Abs(s.CostUSD-0.33) > 1e-9 {
+		t.Errorf("Expected total cost 0.33, got %f", s.CostUSD)
+	}
+}
+
+func TestSummarizeTiming(t *testing.T) {
+	outcome := func(agentMs, judgeMs, firstMs int64, toolCalls ...convex.ToolCall) taskOutcome {
+		return taskOutcome{
+			saved: true,
+			result: &convex.TaskResult{
+				Evaluation:          &convex.Evaluation{Passed: true},
+				ToolCalls:           toolCalls,
+				AgentDurationMs:     agentMs,
+				JudgeDurationMs:     judgeMs,
+				TimeToFirstActionMs: firstMs,
+			},
+		}
+	}
+	tool := func(name string, durationMs int64, isError bool) convex.ToolCall {
+		return convex.ToolCall{ToolName: name, StartedAt: 1, DurationMs: durationMs, IsError: isError}
+	}
+	s := summarize("run-1", []taskOutcome{
+		outcome(60_000, 8_000, 3_000, tool("browser_state", 400, false), tool("python", 2_000, false)),
+		outcome(90_000, 12_000, 5_000, tool("browser_state", 600, true), tool("browser_state", 800, false)),
+	}, 0, 1)
+
+	if s.AgentDurations.MaxMs != 90_000 || s.JudgeDurations.P50Ms != 8_000 || s.FirstAction.MaxMs != 5_000 {
+		t.Errorf("Unexpected timing stats: agent %+v judge %+v first action %+v", s.AgentDurations, s.JudgeDurations, s.FirstAction)
+	}
+	if len(s.Tools) != 2 || s.Tools[0].Tool != "browser_state" {
+		t.Fatalf("Unexpected tools: %+v", s.Tools)
+	}
+	browserState := s.Tools[0]
+	if browserState.Calls != 3 || browserState.Errors != 1 || browserState.P50Ms != 600 || browserState.MaxMs != 800 {
+		t.Errorf("Unexpected browser_state timing: %+v", browserState)
+	}
+}
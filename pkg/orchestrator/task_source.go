@@ -0,0 +1,217 @@
+package orchestrator
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// TaskSource loads the tasks of a dataset.
+type TaskSource interface {
+	FetchTasks(ctx context.Context) ([]convex.Task, error)
+}
+
+// ConvexTaskSource loads a named test case from Convex.
+type ConvexTaskSource struct {
+	Client   *convex.Client
+	TestCase string
+}
+
+// FetchTasks fetches the test case from Convex
+func (s *ConvexTaskSource) FetchTasks(ctx context.Context) ([]convex.Task, error) {
+	return s.Client.FetchTestCase(ctx, s.TestCase)
+}
+
+// FileTaskSource loads tasks from a local JSON array, JSONL or CSV file.
+// JSON and JSONL records use the same field names as convex.Task.
+type FileTaskSource struct {
+	Path string
+	// Columns maps convex.Task JSON field names (task_id, confirmed_task, ...)
+	// to CSV header names. Unmapped fields are read from a column of the same name.
+	Columns map[string]string
+}
+
+// IsTaskFile reports whether a dataset name refers to a local task file
+// rather than a Convex test case.
+func IsTaskFile(dataset string) bool {
+	switch strings.ToLower(filepath.Ext(dataset)) {
+	case ".json", ".jsonl", ".ndjson", ".csv":
+		return true
+	}
+	info, err := os.Stat(dataset)
+	return err == nil && !info.IsDir()
+}
+
+// FetchTasks reads and validates the tasks in the file
+func (s *FileTaskSource) FetchTasks(_ context.Context) ([]convex.Task, error) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("open dataset file failed: %w", err)
+	}
+	defer f.Close()
+
+	var tasks []convex.Task
+	switch strings.ToLower(filepath.Ext(s.Path)) {
+	case ".jsonl", ".ndjson":
+		tasks, err = readJSONLTasks(f)
+	case ".csv":
+		tasks, err = readCSVTasks(f, s.Columns)
+	default:
+		err = json.NewDecoder(f).Decode(&tasks)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse dataset file %s failed: %w", s.Path, err)
+	}
+
+	for i, task := range tasks {
+		if task.ID == "" {
+			return nil, fmt.Errorf("task %d in %s has no task_id", i, s.Path)
+		}
+		if strings.TrimSpace(task.Text) == "" {
+			return nil, fmt.Errorf("task %s in %s has no confirmed_task", task.ID, s.Path)
+		}
+	}
+
+	return tasks, nil
+}
+
+// readJSONLTasks reads one task per non-empty line
+func readJSONLTasks(r io.Reader) ([]convex.Task, error) {
+	var tasks []convex.Task
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var task convex.Task
+		if err := json.Unmarshal([]byte(text), &task); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, scanner.Err()
+}
+
+// csvTaskFields sets a convex.Task field from a CSV cell, keyed by JSON field name
+var csvTaskFields = map[string]func(t *convex.Task, value string) error{
+	"task_id":         func(t *convex.Task, v string) error { t.ID = v; return nil },
+	"runId":           func(t *convex.Task, v string) error { t.RunID = v; return nil },
+	"confirmed_task":  func(t *convex.Task, v string) error { t.Text = v; return nil },
+	"website":         func(t *convex.Task, v string) error { t.Website = v; return nil },
+	"loginCookie":     func(t *convex.Task, v string) error { t.LoginCookie = v; return nil },
+	"browserProvider": func(t *convex.Task, v string) error { t.BrowserProvider = v; return nil },
+	"category":        func(t *convex.Task, v string) error { t.Category = v; return nil },
+	"model":           func(t *convex.Task, v string) error { t.Model = v; return nil },
+	"maxSteps": func(t *convex.Task, v string) error {
+		if v == "" {
+			return nil
+		}
+		steps, err := strconv.Atoi(v)
+		t.MaxSteps = steps
+		return err
+	},
+	"timeoutSeconds": func(t *convex.Task, v string) error {
+		if v == "" {
+			return nil
+		}
+		seconds, err := strconv.Atoi(v)
+		t.TimeoutSeconds = seconds
+		return err
+	},
+	"outputSchema": func(t *convex.Task, v string) error {
+		if v == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(v), &t.OutputSchema)
+	},
+}
+
+// readCSVTasks reads tasks from a CSV file with a header row
+func readCSVTasks(r io.Reader, columns map[string]string) ([]convex.Task, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	headerIndex := make(map[string]int, len(header))
+	for i, name := range header {
+		headerIndex[strings.TrimSpace(name)] = i
+	}
+
+	// Resolve which column feeds each task field
+	fieldIndex := make(map[string]int)
+	for field := range csvTaskFields {
+		column := field
+		if mapped, ok := columns[field]; ok {
+			column = mapped
+		}
+		if idx, ok := headerIndex[column]; ok {
+			fieldIndex[field] = idx
+		}
+	}
+	for field, column := range columns {
+		if _, known := csvTaskFields[field]; !known {
+			return nil, fmt.Errorf("unknown task field in column mapping: %s", field)
+		}
+		if _, found := fieldIndex[field]; !found {
+			return nil, fmt.Errorf("column %q mapped to %s not found in CSV header", column, field)
+		}
+	}
+
+	var tasks []convex.Task
+	for row := 2; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+
+		var task convex.Task
+		for field, idx := range fieldIndex {
+			if idx >= len(record) {
+				continue
+			}
+			if err := csvTaskFields[field](&task, record[idx]); err != nil {
+				return nil, fmt.Errorf("row %d, field %s: %w", row, field, err)
+			}
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// ParseColumnMapping parses "field=column,field=column" into a column mapping
+func ParseColumnMapping(spec string) (map[string]string, error) {
+	columns := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		field, column, ok := strings.Cut(pair, "=")
+		if !ok || field == "" || column == "" {
+			return nil, fmt.Errorf("invalid column mapping %q (want field=column)", pair)
+		}
+		columns[strings.TrimSpace(field)] = strings.TrimSpace(column)
+	}
+	return columns, nil
+}
@@ -0,0 +1,113 @@
+package orchestrator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTaskFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write task file: %v", err)
+	}
+	return path
+}
+
+func TestFileTaskSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		columns  map[string]string
+		wantIDs  []string
+		wantText string
+		wantErr  bool
+	}{
+		{
+			name:     "json array",
+			file:     "tasks.json",
+			content:  `[{"task_id": "1", "confirmed_task": "Find a cat", "category": "Search"}, {"task_id": "2", "confirmed_task": "Find a dog"}]`,
+			wantIDs:  []string{"1", "2"},
+			wantText: "Find a cat",
+		},
+		{
+			name:     "jsonl with blank lines",
+			file:     "tasks.jsonl",
+			content:  "{\"task_id\": \"a\", \"confirmed_task\": \"First\"}\n\n{\"task_id\": \"b\", \"confirmed_task\": \"Second\"}\n",
+			wantIDs:  []string{"a", "b"},
+			wantText: "First",
+		},
+		{
+			name:     "csv with default headers",
+			file:     "tasks.csv",
+			content:  "task_id,confirmed_task,category\n7,\"Open example.com, then stop\",Search\n",
+			wantIDs:  []string{"7"},
+			wantText: "Open example.com, then stop",
+		},
+		{
+			name:     "csv with column mapping",
+			file:     "tasks.csv",
+			content:  "id,prompt\nx1,Book a table\n",
+			columns:  map[string]string{"task_id": "id", "confirmed_task": "prompt"},
+			wantIDs:  []string{"x1"},
+			wantText: "Book a table",
+		},
+		{
+			name:    "csv mapping to missing column",
+			file:    "tasks.csv",
+			content: "id,prompt\nx1,Book a table\n",
+			columns: map[string]string{"task_id": "identifier"},
+			wantErr: true,
+		},
+		{
+			name:    "missing task text",
+			file:    "tasks.json",
+			content: `[{"task_id": "1"}]`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &FileTaskSource{Path: writeTaskFile(t, tt.file, tt.content), Columns: tt.columns}
+			tasks, err := source.FetchTasks(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error, got %d tasks", len(tasks))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchTasks failed: %v", err)
+			}
+			if len(tasks) != len(tt.wantIDs) {
+				t.Fatalf("Expected %d tasks, got %d", len(tt.wantIDs), len(tasks))
+			}
+			for i, id := range tt.wantIDs {
+				if tasks[i].ID != id {
+					t.Errorf("Task %d: expected ID %s, got %s", i, id, tasks[i].ID)
+				}
+			}
+			if tasks[0].Text != tt.wantText {
+				t.Errorf("Expected text %q, got %q", tt.wantText, tasks[0].Text)
+			}
+		})
+	}
+}
+
+func TestParseColumnMapping(t *testing.T) {
+	columns, err := ParseColumnMapping("task_id=id, confirmed_task=prompt")
+	if err != nil {
+		t.Fatalf("ParseColumnMapping failed: %v", err)
+	}
+	if columns["task_id"] != "id" || columns["confirmed_task"] != "prompt" {
+		t.Errorf("Unexpected mapping: %v", columns)
+	}
+
+	if _, err := ParseColumnMapping("task_id"); err == nil {
+		t.Error("Expected error for mapping without '='")
+	}
+}
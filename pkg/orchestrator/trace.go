@@ -0,0 +1,216 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+	"mix-eval-go/pkg/sse"
+)
+
+// Trace artifacts live in <run dir>/traces/<task>/<Mix session ID>/, one
+// directory per attempt
+const (
+	tracesDirName       = "traces"
+	traceTaskFile       = "task.json"
+	traceEventsFile     = "events.jsonl"
+	traceMessagesFile   = "messages.json"
+	traceScreenshotsDir = "screenshots"
+)
+
+// TraceEvent is one SSE event as received from Mix
+type TraceEvent struct {
+	ReceivedAt time.Time       `json:"receivedAt"`
+	Type       string          `json:"type"`
+	ID         string          `json:"id,omitempty"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// traceWriter records an attempt's task, raw SSE events, message history and
+// screenshots. A nil *traceWriter records nothing.
+type traceWriter struct {
+	dir string
+
+	mu     sync.Mutex
+	events *os.File
+	enc    *json.Encoder
+	count  int
+	err    error
+}
+
+// traceDir returns the trace directory of one attempt at a task
+func traceDir(outputDir string, task convex.Task, sessionID string) string {
+	return filepath.Join(runDir(outputDir, task.RunID), tracesDirName,
+		safePathComponent(checkpointKey(task)), safePathComponent(sessionID))
+}
+
+// newTraceWriter creates the trace directory and its event log
+func newTraceWriter(dir string) (*traceWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create trace dir failed: %w", err)
+	}
+	f, err := os.Create(filepath.Join(dir, traceEventsFile))
+	if err != nil {
+		return nil, fmt.Errorf("create trace events failed: %w", err)
+	}
+	return &traceWriter{dir: dir, events: f, enc: json.NewEncoder(f)}, nil
+}
+
+// event appends a raw event; the first write error is kept for close
+func (w *traceWriter) event(ev sse.Event, receivedAt time.Time) {
+	if w == nil {
+		return
+	}
+
+	data := json.RawMessage(ev.Data)
+	if !json.Valid(data) {
+		data, _ = json.Marshal(ev.Data)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err != nil || w.events == nil {
+		return
+	}
+	if err := w.enc.Encode(TraceEvent{ReceivedAt: receivedAt, Type: ev.Type, ID: ev.ID, Data: data}); err != nil {
+		w.err = fmt.Errorf("write trace event failed: %w", err)
+		return
+	}
+	w.count++
+}
+
+// writeTask writes the task being attempted
+func (w *traceWriter) writeTask(task convex.Task) error {
+	if w == nil {
+		return nil
+	}
+	if err := writeJSONFile(filepath.Join(w.dir, traceTaskFile), task); err != nil {
+		return fmt.Errorf("write trace task failed: %w", err)
+	}
+	return nil
+}
+
+// writeMessages writes the session's message history
+func (w *traceWriter) writeMessages(messages any) error {
+	if w == nil {
+		return nil
+	}
+	if err := writeJSONFile(filepath.Join(w.dir, traceMessagesFile), messages); err != nil {
+		return fmt.Errorf("write trace messages failed: %w", err)
+	}
+	return nil
+}
+
+// writeScreenshots writes the screenshots shown to the judge, numbered in order
+func (w *traceWriter) writeScreenshots(screenshots [][]byte) error {
+	if w == nil || len(screenshots) == 0 {
+		return nil
+	}
+	dir := filepath.Join(w.dir, traceScreenshotsDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create trace screenshots dir failed: %w", err)
+	}
+	for i, screenshot := range screenshots {
+		name := fmt.Sprintf("%03d%s", i+1, imageExtension(screenshot))
+		if err := os.WriteFile(filepath.Join(dir, name), screenshot, 0o644); err != nil {
+			return fmt.Errorf("write trace screenshot failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// imageExtension picks a file extension from an image's content
+func imageExtension(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	default:
+		return ".bin"
+	}
+}
+
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// close closes the event log and reports the first write error; it is safe
+// to call more than once
+func (w *traceWriter) close() error {
+	if w == nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.events != nil {
+		if err := w.events.Close(); err != nil && w.err == nil {
+			w.err = fmt.Errorf("close trace events failed: %w", err)
+		}
+		w.events = nil
+	}
+	return w.err
+}
+
+// openTrace starts the trace of an attempt, or returns nil when traces are
+// disabled or cannot be written
+func (o *Orchestrator) openTrace(ctx context.Context, task convex.Task, sessionID string) *traceWriter {
+	if !o.config.Traces || o.config.OutputDir == "" {
+		return nil
+	}
+	trace, err := newTraceWriter(traceDir(o.config.OutputDir, task, sessionID))
+	if err != nil {
+		loggerFrom(ctx).Warn("trace capture disabled", "error", err)
+		return nil
+	}
+	if err := trace.writeTask(task); err != nil {
+		loggerFrom(ctx).Warn("trace incomplete", "error", err)
+	}
+	return trace
+}
+
+// finishTrace closes a trace and returns its reference for the task result,
+// uploading the artifacts to Convex storage when configured
+func (o *Orchestrator) finishTrace(ctx context.Context, trace *traceWriter) *convex.TraceRef {
+	if trace == nil {
+		return nil
+	}
+	logger := loggerFrom(ctx)
+	if err := trace.close(); err != nil {
+		logger.Warn("trace incomplete", "error", err)
+	}
+
+	ref := &convex.TraceRef{Path: trace.dir, Events: trace.count}
+	if !o.config.UploadTraces || o.config.ConvexURL == "" {
+		return ref
+	}
+
+	upload := func(name, contentType string) string {
+		data, err := os.ReadFile(filepath.Join(trace.dir, name))
+		if err == nil {
+			var storageID string
+			if storageID, err = o.convexClient.UploadFile(ctx, data, contentType); err == nil {
+				return storageID
+			}
+		}
+		logger.Warn("trace upload failed", "file", name, "error", err)
+		return ""
+	}
+	ref.EventsStorageID = upload(traceEventsFile, "application/x-ndjson")
+	ref.MessagesStorageID = upload(traceMessagesFile, "application/json")
+	return ref
+}
@@ -0,0 +1,112 @@
+package orchestrator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mix-eval-go/pkg/convex"
+	"mix-eval-go/pkg/sse"
+)
+
+func readTraceEvents(t *testing.T, dir string) []TraceEvent {
+	t.Helper()
+	f, err := os.Open(filepath.Join(dir, traceEventsFile))
+	if err != nil {
+		t.Fatalf("Open trace events failed: %v", err)
+	}
+	defer f.Close()
+
+	var events []TraceEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev TraceEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("Invalid trace line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestTraceWriter(t *testing.T) {
+	outputDir := t.TempDir()
+	task := convex.Task{ID: "task/1", RunID: "run-1", Trial: 2}
+	dir := traceDir(outputDir, task, "session-1")
+	if want := filepath.Join(outputDir, "run-1", tracesDirName, "task_1#2", "session-1"); dir != want {
+		t.Errorf("Expected trace dir %s, got %s", want, dir)
+	}
+
+	trace, err := newTraceWriter(dir)
+	if err != nil {
+		t.Fatalf("newTraceWriter failed: %v", err)
+	}
+	receivedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	trace.event(sse.Event{Type: "content", ID: "7", Data: `{"type":"content","content":"hi"}`}, receivedAt)
+	trace.event(sse.Event{Type: "message", Data: "not json"}, receivedAt)
+	if err := trace.writeMessages([]map[string]string{{"role": "user"}}); err != nil {
+		t.Fatalf("writeMessages failed: %v", err)
+	}
+	if err := trace.close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	trace.event(sse.Event{Type: "late"}, receivedAt) // dropped after close
+
+	events := readTraceEvents(t, dir)
+	if len(events) != 2 || trace.count != 2 {
+		t.Fatalf("Expected 2 trace events, got %d (count %d)", len(events), trace.count)
+	}
+	if events[0].ID != "7" || !events[0].ReceivedAt.Equal(receivedAt) || string(events[0].Data) != `{"type":"content","content":"hi"}` {
+		t.Errorf("Unexpected first event: %+v", events[0])
+	}
+	if string(events[1].Data) != `"not json"` {
+		t.Errorf("Expected non-JSON data to be quoted, got %s", events[1].Data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, traceMessagesFile)); err != nil {
+		t.Errorf("Expected messages file: %v", err)
+	}
+
+	// A nil trace records nothing
+	var disabled *traceWriter
+	disabled.event(sse.Event{}, receivedAt)
+	if err := disabled.writeMessages(nil); err != nil || disabled.close() != nil {
+		t.Error("Expected a nil trace to be a no-op")
+	}
+}
+
+func TestEventStreamTracesRawEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: connected\nid: 1\ndata: {\"sessionId\":\"s\"}\n\n")
+		fmt.Fprint(w, "event: heartbeat\nid: 2\ndata: {\"type\":\"heartbeat\"}\n\n")
+		fmt.Fprint(w, "event: complete\nid: 3\ndata: {\"type\":\"complete\"}\n\n")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	trace, err := newTraceWriter(dir)
+	if err != nil {
+		t.Fatalf("newTraceWriter failed: %v", err)
+	}
+	stream := newEventStream(server.URL, "s", slog.Default())
+	stream.trace = trace
+	go stream.run(context.Background())
+	for range stream.events {
+	}
+	<-stream.done
+	trace.close()
+
+	// Events the stream filters out are still traced
+	events := readTraceEvents(t, dir)
+	if len(events) != 3 || events[0].Type != "connected" || events[1].Type != "heartbeat" {
+		t.Errorf("Expected all raw events in the trace, got %+v", events)
+	}
+}
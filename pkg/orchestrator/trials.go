@@ -0,0 +1,123 @@
+package orchestrator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// TrialStats aggregates the independent trials of one task
+type TrialStats struct {
+	TaskID    string  `json:"taskId"`
+	Trials    int     `json:"trials"`
+	Passed    int     `json:"passed"`
+	K         int     `json:"k"`
+	PassAtK   float64 `json:"passAtK"`  // probability at least one of k trials passes
+	PassHatK  float64 `json:"passHatK"` // probability all k trials pass
+	MeanScore float64 `json:"meanScore"`
+}
+
+// expandTrials repeats every task for each trial, numbered from 1 (0 means
+// the run has no trials). Trials are ordered trial-major so repeats of the
+// same task do not run side by side.
+func expandTrials(tasks []convex.Task, trials int) []convex.Task {
+	if trials <= 1 {
+		return tasks
+	}
+	expanded := make([]convex.Task, 0, len(tasks)*trials)
+	for trial := 1; trial <= trials; trial++ {
+		for _, task := range tasks {
+			task.Trial = trial
+			expanded = append(expanded, task)
+		}
+	}
+	return expanded
+}
+
+// AggregateTrials computes per-task pass@k, pass^k and mean score. Tasks with
+// fewer than k recorded trials (e.g. an interrupted run) use k = trials.
+func AggregateTrials(results []*convex.TaskResult, k int) []TrialStats {
+	byTask := make(map[string][]*convex.TaskResult)
+	var order []string
+	for _, r := range results {
+		if _, seen := byTask[r.TaskID]; !seen {
+			order = append(order, r.TaskID)
+		}
+		byTask[r.TaskID] = append(byTask[r.TaskID], r)
+	}
+	sort.Strings(order)
+
+	stats := make([]TrialStats, 0, len(order))
+	for _, taskID := range order {
+		trials := byTask[taskID]
+		s := TrialStats{TaskID: taskID, Trials: len(trials), K: minInt(k, len(trials))}
+
+		var scoreSum float64
+		for _, r := range trials {
+			if r.Evaluation == nil {
+				continue
+			}
+			if r.Evaluation.Passed {
+				s.Passed++
+			}
+			scoreSum += r.Evaluation.Score
+		}
+		s.MeanScore = scoreSum / float64(len(trials))
+		s.PassAtK = passAtK(s.Trials, s.Passed, s.K)
+		s.PassHatK = passHatK(s.Trials, s.Passed, s.K)
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// passAtK is the unbiased estimate of P(at least one of k samples passes)
+// given c passes out of n samples: 1 - C(n-c, k) / C(n, k)
+func passAtK(n, c, k int) float64 {
+	if k <= 0 || n <= 0 {
+		return 0
+	}
+	return 1 - binomial(n-c, k)/binomial(n, k)
+}
+
+// passHatK estimates P(all k samples pass) given c passes out of n: C(c, k) / C(n, k)
+func passHatK(n, c, k int) float64 {
+	if k <= 0 || n <= 0 {
+		return 0
+	}
+	return binomial(c, k) / binomial(n, k)
+}
+
+// binomial returns C(n, k) as a float64 (0 when k > n)
+func binomial(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+// printTrialStats renders per-task trial statistics as a table
+func printTrialStats(w io.Writer, stats []TrialStats, k int) {
+	if len(stats) == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "TASK\tTRIALS\tPASSED\tPASS@%d\tPASS^%d\tMEAN SCORE\n", k, k)
+
+	var sumAt, sumHat, sumScore float64
+	for _, s := range stats {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%.2f\t%.2f\n", s.TaskID, s.Trials, s.Passed, s.PassAtK, s.PassHatK, s.MeanScore)
+		sumAt += s.PassAtK
+		sumHat += s.PassHatK
+		sumScore += s.MeanScore
+	}
+	n := float64(len(stats))
+	fmt.Fprintf(tw, "MEAN\t\t\t%.2f\t%.2f\t%.2f\n", sumAt/n, sumHat/n, sumScore/n)
+	tw.Flush()
+}
@@ -0,0 +1,83 @@
+package orchestrator
+
+import (
+	"math"
+	"testing"
+
+	"mix-eval-go/pkg/convex"
+)
+
+func TestAggregateTrials(t *testing.T) {
+	result := func(taskID string, passed bool) *convex.TaskResult {
+		score := 0.0
+		if passed {
+			score = 1.0
+		}
+		return &convex.TaskResult{TaskID: taskID, Evaluation: &convex.Evaluation{Passed: passed, Score: score}}
+	}
+	results := []*convex.TaskResult{
+		result("flaky", true), result("flaky", false), result("flaky", true),
+		result("solid", true), result("solid", true), result("solid", true),
+		result("broken", false), result("broken", false), result("broken", false),
+	}
+
+	tests := []struct {
+		taskID        string
+		k             int
+		wantPassAt    float64
+		wantPassHat   float64
+		wantMeanScore float64
+	}{
+		{"flaky", 3, 1.0, 0.0, 2.0 / 3.0},
+		{"flaky", 1, 2.0 / 3.0, 2.0 / 3.0, 2.0 / 3.0},
+		{"flaky", 2, 1.0, 1.0 / 3.0, 2.0 / 3.0},
+		{"solid", 3, 1.0, 1.0, 1.0},
+		{"broken", 3, 0.0, 0.0, 0.0},
+	}
+
+	for _, tt := range tests {
+		var got *TrialStats
+		stats := AggregateTrials(results, tt.k)
+		for i := range stats {
+			if stats[i].TaskID == tt.taskID {
+				got = &stats[i]
+			}
+		}
+		if got == nil {
+			t.Fatalf("No stats for task %s", tt.taskID)
+		}
+		if !approxEqual(got.PassAtK, tt.wantPassAt) {
+			t.Errorf("%s k=%d: expected pass@k %.3f, got %.3f", tt.taskID, tt.k, tt.wantPassAt, got.PassAtK)
+		}
+		if !approxEqual(got.PassHatK, tt.wantPassHat) {
+			t.Errorf("%s k=%d: expected pass^k %.3f, got %.3f", tt.taskID, tt.k, tt.wantPassHat, got.PassHatK)
+		}
+		if !approxEqual(got.MeanScore, tt.wantMeanScore) {
+			t.Errorf("%s k=%d: expected mean score %.3f, got %.3f", tt.taskID, tt.k, tt.wantMeanScore, got.MeanScore)
+		}
+	}
+}
+
+func TestExpandTrials(t *testing.T) {
+	tasks := expandTrials([]convex.Task{{ID: "a"}, {ID: "b"}}, 2)
+	want := []struct {
+		id    string
+		trial int
+	}{{"a", 1}, {"b", 1}, {"a", 2}, {"b", 2}}
+	if len(tasks) != len(want) {
+		t.Fatalf("Expected %d tasks, got %d", len(want), len(tasks))
+	}
+	for i, w := range want {
+		if tasks[i].ID != w.id || tasks[i].Trial != w.trial {
+			t.Errorf("Task %d: expected %s trial %d, got %s trial %d", i, w.id, w.trial, tasks[i].ID, tasks[i].Trial)
+		}
+	}
+
+	if single := expandTrials([]convex.Task{{ID: "a"}}, 1); single[0].Trial != 0 {
+		t.Errorf("Expected a run without trials to leave the trial unset, got %d", single[0].Trial)
+	}
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,54 @@
+package orchestrator
+
+import (
+	"context"
+	"sync"
+
+	"mix-eval-go/pkg/convex"
+)
+
+// usageMeter accumulates the token usage of judge LLM calls
+type usageMeter struct {
+	mu    sync.Mutex
+	usage convex.TokenUsage
+}
+
+// add records one call, priced from judgePricing
+func (m *usageMeter) add(model string, inputTokens, outputTokens int64) {
+	call := convex.TokenUsage{Calls: 1, InputTokens: inputTokens, OutputTokens: outputTokens}
+	if pricing, ok := judgePricing[model]; ok {
+		call.CostUSD = pricing.Cost(inputTokens, outputTokens)
+	} else {
+		call.Unpriced = true
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.usage.Add(call)
+}
+
+// total returns the accumulated usage, or nil when no call was recorded
+func (m *usageMeter) total() *convex.TokenUsage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.usage.Calls == 0 {
+		return nil
+	}
+	usage := m.usage
+	return &usage
+}
+
+// usageKey carries a *usageMeter in a context
+type usageKey struct{}
+
+// withUsageMeter returns a context whose judge LLM calls are recorded in meter
+func withUsageMeter(ctx context.Context, meter *usageMeter) context.Context {
+	return context.WithValue(ctx, usageKey{}, meter)
+}
+
+// recordUsage adds a judge LLM call to the context's meter, if any
+func recordUsage(ctx context.Context, model string, inputTokens, outputTokens int64) {
+	if meter, ok := ctx.Value(usageKey{}).(*usageMeter); ok {
+		meter.add(model, inputTokens, outputTokens)
+	}
+}
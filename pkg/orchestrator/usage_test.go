@@ -0,0 +1,60 @@
+package orchestrator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/recreate-run/mix-go-sdk/models/components"
+)
+
+func TestUsageMeter(t *testing.T) {
+	meter := &usageMeter{}
+	ctx := withUsageMeter(context.Background(), meter)
+
+	if meter.total() != nil {
+		t.Error("Expected no usage before any call")
+	}
+
+	recordUsage(ctx, ModelGemini3Flash, 1_000_000, 100_000)
+	recordUsage(ctx, ModelGemini3Flash, 500_000, 0)
+	recordUsage(context.Background(), ModelGemini3Flash, 1, 1) // not metered
+
+	usage := meter.total()
+	if usage.Calls != 2 || usage.InputTokens != 1_500_000 || usage.OutputTokens != 100_000 {
+		t.Errorf("Unexpected usage: %+v", usage)
+	}
+	if want := 0.5 + 0.3 + 0.25; math.Abs(usage.CostUSD-want) > 1e-9 {
+		t.Errorf("Expected cost %.4f, got %.4f", want, usage.CostUSD)
+	}
+	if usage.Unpriced {
+		t.Error("Expected known model to be priced")
+	}
+
+	recordUsage(ctx, "unknown-model", 10, 10)
+	if usage := meter.total(); !usage.Unpriced || usage.Calls != 3 {
+		t.Errorf("Expected unknown model to mark usage unpriced: %+v", usage)
+	}
+}
+
+func TestExtractHistoryUsage(t *testing.T) {
+	in, out, cost := int64(1200), int64(300), 0.0125
+	messages := []components.BackendMessage{
+		{InputTokens: &in, OutputTokens: &out, Cost: &cost},
+		{InputTokens: &in, OutputTokens: &out, Cost: &cost},
+		{}, // user message without usage
+	}
+
+	history := extractHistory(messages)
+	if history.InputTokens != 2400 || history.OutputTokens != 600 || history.TokensUsed != 3000 {
+		t.Errorf("Unexpected token counts: %+v", history)
+	}
+	usage := history.agentUsage()
+	if usage == nil || math.Abs(usage.CostUSD-0.025) > 1e-9 {
+		t.Errorf("Unexpected agent usage: %+v", usage)
+	}
+
+	if extractHistory(nil).agentUsage() != nil {
+		t.Error("Expected no agent usage when Mix reports none")
+	}
+}
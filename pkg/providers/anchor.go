@@ -0,0 +1,98 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// AnchorBrowserOptions configures the Anchor Browser provider
+type AnchorBrowserOptions struct {
+	APIKey string
+	// BaseURL overrides the API URL (default https://api.anchorbrowser.io)
+	BaseURL string
+	// HTTPClient overrides the client (default: 30s timeout)
+	HTTPClient *http.Client
+}
+
+// AnchorBrowser creates sessions on https://anchorbrowser.io
+type AnchorBrowser struct {
+	opts   AnchorBrowserOptions
+	client *http.Client
+}
+
+// NewAnchorBrowser creates the Anchor Browser provider
+func NewAnchorBrowser(opts AnchorBrowserOptions) *AnchorBrowser {
+	opts.BaseURL = baseURLOrDefault(opts.BaseURL, "https://api.anchorbrowser.io")
+	return &AnchorBrowser{opts: opts, client: httpClientOrDefault(opts.HTTPClient)}
+}
+
+// Name returns ProviderAnchorBrowser
+func (a *AnchorBrowser) Name() BrowserProvider {
+	return ProviderAnchorBrowser
+}
+
+func (a *AnchorBrowser) headers() map[string]string {
+	return map[string]string{"anchor-api-key": a.opts.APIKey}
+}
+
+func (a *AnchorBrowser) configured() error {
+	if a.opts.APIKey == "" {
+		return fmt.Errorf("%w: anchor needs an API key", ErrNotConfigured)
+	}
+	return nil
+}
+
+// Create starts an Anchor session with captcha solving enabled
+func (a *AnchorBrowser) Create(ctx context.Context) (*BrowserSession, error) {
+	if err := a.configured(); err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Data struct {
+			ID     string `json:"id"`
+			CDPURL string `json:"cdp_url"`
+		} `json:"data"`
+	}
+	body := map[string]interface{}{
+		"browser": map[string]interface{}{
+			"captcha_solver": map[string]bool{"active": true},
+		},
+	}
+	if err := doJSON(ctx, a.client, http.MethodPost, a.opts.BaseURL+"/v1/sessions", a.headers(), body, &result); err != nil {
+		return nil, fmt.Errorf("anchor create session failed: %w", err)
+	}
+	if result.Data.CDPURL == "" {
+		if result.Data.ID != "" {
+			_ = a.Close(ctx, &BrowserSession{SessionID: result.Data.ID})
+		}
+		return nil, fmt.Errorf("anchor create session failed: response has no cdp_url")
+	}
+
+	return &BrowserSession{
+		CDPURL:    result.Data.CDPURL,
+		SessionID: result.Data.ID,
+		Provider:  ProviderAnchorBrowser,
+	}, nil
+}
+
+// Close ends the session
+func (a *AnchorBrowser) Close(ctx context.Context, session *BrowserSession) error {
+	url := fmt.Sprintf("%s/v1/sessions/%s", a.opts.BaseURL, session.SessionID)
+	if err := doJSON(ctx, a.client, http.MethodDelete, url, a.headers(), nil, nil); err != nil {
+		return fmt.Errorf("anchor end session failed: %w", err)
+	}
+	return nil
+}
+
+// HealthCheck queries session status to verify the API key
+func (a *AnchorBrowser) HealthCheck(ctx context.Context) error {
+	if err := a.configured(); err != nil {
+		return err
+	}
+	if err := checkAPI(ctx, a.client, a.opts.BaseURL+"/v1/sessions/all/status", a.headers()); err != nil {
+		return fmt.Errorf("anchor health check failed: %w", err)
+	}
+	return nil
+}
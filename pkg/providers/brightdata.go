@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// BrightdataOptions configures the Bright Data Scraping Browser provider
+type BrightdataOptions struct {
+	Username string
+	Password string
+	// Host overrides the CDP endpoint (default brd.superproxy.io:9222)
+	Host string
+}
+
+// Brightdata connects to the Bright Data Scraping Browser. There is no
+// session API: each CDP connection is its own session.
+type Brightdata struct {
+	opts BrightdataOptions
+}
+
+// NewBrightdata creates the Bright Data provider
+func NewBrightdata(opts BrightdataOptions) *Brightdata {
+	if opts.Host == "" {
+		opts.Host = "brd.superproxy.io:9222"
+	}
+	return &Brightdata{opts: opts}
+}
+
+// Name returns ProviderBrightdata
+func (b *Brightdata) Name() BrowserProvider {
+	return ProviderBrightdata
+}
+
+// Create returns the authenticated CDP endpoint
+func (b *Brightdata) Create(ctx context.Context) (*BrowserSession, error) {
+	if err := b.HealthCheck(ctx); err != nil {
+		return nil, err
+	}
+	cdpURL := url.URL{
+		Scheme: "wss",
+		User:   url.UserPassword(b.opts.Username, b.opts.Password),
+		Host:   b.opts.Host,
+	}
+	return &BrowserSession{
+		CDPURL:    cdpURL.String(),
+		SessionID: "brightdata-session",
+		Provider:  ProviderBrightdata,
+	}, nil
+}
+
+// Close is a no-op: the session ends when the CDP connection closes
+func (b *Brightdata) Close(ctx context.Context, session *BrowserSession) error {
+	return nil
+}
+
+// HealthCheck verifies credentials are configured (there is no API to call)
+func (b *Brightdata) HealthCheck(ctx context.Context) error {
+	if b.opts.Username == "" || b.opts.Password == "" {
+		return fmt.Errorf("%w: brightdata needs a username and password", ErrNotConfigured)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// BrowserbaseOptions configures the Browserbase provider
+type BrowserbaseOptions struct {
+	APIKey    string
+	ProjectID string
+	// BaseURL overrides the API URL (default https://api.browserbase.com)
+	BaseURL string
+	// HTTPClient overrides the client (default: 30s timeout)
+	HTTPClient *http.Client
+}
+
+// Browserbase creates sessions on https://www.browserbase.com
+type Browserbase struct {
+	opts   BrowserbaseOptions
+	client *http.Client
+}
+
+// NewBrowserbase creates the Browserbase provider
+func NewBrowserbase(opts BrowserbaseOptions) *Browserbase {
+	opts.BaseURL = baseURLOrDefault(opts.BaseURL, "https://api.browserbase.com")
+	return &Browserbase{opts: opts, client: httpClientOrDefault(opts.HTTPClient)}
+}
+
+// Name returns ProviderBrowserbase
+func (b *Browserbase) Name() BrowserProvider {
+	return ProviderBrowserbase
+}
+
+func (b *Browserbase) headers() map[string]string {
+	return map[string]string{"X-BB-API-Key": b.opts.APIKey}
+}
+
+func (b *Browserbase) configured() error {
+	if b.opts.APIKey == "" || b.opts.ProjectID == "" {
+		return fmt.Errorf("%w: browserbase needs an API key and project ID", ErrNotConfigured)
+	}
+	return nil
+}
+
+// Create starts a Browserbase session
+func (b *Browserbase) Create(ctx context.Context) (*BrowserSession, error) {
+	if err := b.configured(); err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		ID         string `json:"id"`
+		ConnectURL string `json:"connectUrl"`
+	}
+	body := map[string]string{"projectId": b.opts.ProjectID}
+	if err := doJSON(ctx, b.client, http.MethodPost, b.opts.BaseURL+"/v1/sessions", b.headers(), body, &result); err != nil {
+		return nil, fmt.Errorf("browserbase create session failed: %w", err)
+	}
+	if result.ConnectURL == "" {
+		if result.ID != "" {
+			_ = b.Close(ctx, &BrowserSession{SessionID: result.ID})
+		}
+		return nil, fmt.Errorf("browserbase create session failed: response has no connectUrl")
+	}
+
+	return &BrowserSession{
+		CDPURL:    result.ConnectURL,
+		SessionID: result.ID,
+		Provider:  ProviderBrowserbase,
+	}, nil
+}
+
+// Close asks Browserbase to release the session
+func (b *Browserbase) Close(ctx context.Context, session *BrowserSession) error {
+	body := map[string]string{"projectId": b.opts.ProjectID, "status": "REQUEST_RELEASE"}
+	url := fmt.Sprintf("%s/v1/sessions/%s", b.opts.BaseURL, session.SessionID)
+	if err := doJSON(ctx, b.client, http.MethodPost, url, b.headers(), body, nil); err != nil {
+		return fmt.Errorf("browserbase release session failed: %w", err)
+	}
+	return nil
+}
+
+// HealthCheck looks up the configured project
+func (b *Browserbase) HealthCheck(ctx context.Context) error {
+	if err := b.configured(); err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/v1/projects/%s", b.opts.BaseURL, b.opts.ProjectID)
+	if err := checkAPI(ctx, b.client, url, b.headers()); err != nil {
+		return fmt.Errorf("browserbase health check failed: %w", err)
+	}
+	return nil
+}
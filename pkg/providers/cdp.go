@@ -0,0 +1,77 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// cdpConn is a minimal Chrome DevTools Protocol client on a browser's
+// websocket debugger URL: commands run one at a time and events are ignored
+type cdpConn struct {
+	ws     *websocket.Conn
+	nextID int
+}
+
+// dialCDP connects to a websocket debugger URL
+func dialCDP(ctx context.Context, wsURL string) (*cdpConn, error) {
+	ws, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s failed: %w", wsURL, err)
+	}
+	return &cdpConn{ws: ws}, nil
+}
+
+func (c *cdpConn) Close() error {
+	return c.ws.Close()
+}
+
+// call sends a command and decodes its result into result, which may be nil
+func (c *cdpConn) call(ctx context.Context, method string, params, result any) error {
+	if deadline, ok := ctx.Deadline(); ok {
+		c.ws.SetWriteDeadline(deadline)
+		c.ws.SetReadDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { c.ws.SetReadDeadline(time.Now()) })
+	defer stop()
+
+	c.nextID++
+	command := struct {
+		ID     int    `json:"id"`
+		Method string `json:"method"`
+		Params any    `json:"params,omitempty"`
+	}{c.nextID, method, params}
+	if err := c.ws.WriteJSON(command); err != nil {
+		return fmt.Errorf("%s failed: %w", method, err)
+	}
+
+	for {
+		var reply struct {
+			ID     int             `json:"id"`
+			Result json.RawMessage `json:"result"`
+			Error  *struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := c.ws.ReadJSON(&reply); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("%s failed: %w", method, ctx.Err())
+			}
+			return fmt.Errorf("%s failed: %w", method, err)
+		}
+		// Events carry no id
+		if reply.ID != command.ID {
+			continue
+		}
+		if reply.Error != nil {
+			return fmt.Errorf("%s failed: %s", method, reply.Error.Message)
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(reply.Result, result)
+	}
+}
@@ -0,0 +1,92 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// HyperbrowserOptions configures the Hyperbrowser provider
+type HyperbrowserOptions struct {
+	APIKey string
+	// BaseURL overrides the API URL (default https://api.hyperbrowser.ai)
+	BaseURL string
+	// HTTPClient overrides the client (default: 30s timeout)
+	HTTPClient *http.Client
+}
+
+// Hyperbrowser creates sessions on https://hyperbrowser.ai
+type Hyperbrowser struct {
+	opts   HyperbrowserOptions
+	client *http.Client
+}
+
+// NewHyperbrowser creates the Hyperbrowser provider
+func NewHyperbrowser(opts HyperbrowserOptions) *Hyperbrowser {
+	opts.BaseURL = baseURLOrDefault(opts.BaseURL, "https://api.hyperbrowser.ai")
+	return &Hyperbrowser{opts: opts, client: httpClientOrDefault(opts.HTTPClient)}
+}
+
+// Name returns ProviderHyperbrowser
+func (h *Hyperbrowser) Name() BrowserProvider {
+	return ProviderHyperbrowser
+}
+
+func (h *Hyperbrowser) headers() map[string]string {
+	return map[string]string{"x-api-key": h.opts.APIKey}
+}
+
+func (h *Hyperbrowser) configured() error {
+	if h.opts.APIKey == "" {
+		return fmt.Errorf("%w: hyperbrowser needs an API key", ErrNotConfigured)
+	}
+	return nil
+}
+
+// Create starts a stealth Hyperbrowser session
+func (h *Hyperbrowser) Create(ctx context.Context) (*BrowserSession, error) {
+	if err := h.configured(); err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		ID         string `json:"id"`
+		WSEndpoint string `json:"wsEndpoint"`
+	}
+	body := map[string]interface{}{"useStealth": true}
+	if err := doJSON(ctx, h.client, http.MethodPost, h.opts.BaseURL+"/api/session", h.headers(), body, &result); err != nil {
+		return nil, fmt.Errorf("hyperbrowser create session failed: %w", err)
+	}
+	if result.WSEndpoint == "" {
+		if result.ID != "" {
+			_ = h.Close(ctx, &BrowserSession{SessionID: result.ID})
+		}
+		return nil, fmt.Errorf("hyperbrowser create session failed: response has no wsEndpoint")
+	}
+
+	return &BrowserSession{
+		CDPURL:    result.WSEndpoint,
+		SessionID: result.ID,
+		Provider:  ProviderHyperbrowser,
+	}, nil
+}
+
+// Close stops the session
+func (h *Hyperbrowser) Close(ctx context.Context, session *BrowserSession) error {
+	url := fmt.Sprintf("%s/api/session/%s/stop", h.opts.BaseURL, session.SessionID)
+	if err := doJSON(ctx, h.client, http.MethodPut, url, h.headers(), nil, nil); err != nil {
+		return fmt.Errorf("hyperbrowser stop session failed: %w", err)
+	}
+	return nil
+}
+
+// HealthCheck lists sessions to verify the API key
+func (h *Hyperbrowser) HealthCheck(ctx context.Context) error {
+	if err := h.configured(); err != nil {
+		return err
+	}
+	if err := checkAPI(ctx, h.client, h.opts.BaseURL+"/api/sessions", h.headers()); err != nil {
+		return fmt.Errorf("hyperbrowser health check failed: %w", err)
+	}
+	return nil
+}
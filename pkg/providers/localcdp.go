@@ -0,0 +1,273 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// LocalCDPOptions configures the local CDP provider
+type LocalCDPOptions struct {
+	// Endpoints are ws:// debugger URLs or http://host:9222 DevTools
+	// endpoints; sessions are spread across them round-robin, one live
+	// session per endpoint
+	Endpoints []string
+	// HTTPClient overrides the client (default: 30s timeout)
+	HTTPClient *http.Client
+}
+
+// LocalCDP hands out self-hosted Chrome instances, e.g. a docker-compose
+// stack or an in-house browser fleet. Each endpoint serves one session at a
+// time, since tasks sharing a browser would see (and reset) each other's tabs.
+type LocalCDP struct {
+	endpoints []string
+	client    *http.Client
+
+	mu     sync.Mutex
+	next   int
+	leased map[string]bool
+}
+
+// NewLocalCDP creates the local CDP provider
+func NewLocalCDP(opts LocalCDPOptions) *LocalCDP {
+	var endpoints []string
+	seen := make(map[string]bool)
+	for _, endpoint := range opts.Endpoints {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" && !seen[endpoint] {
+			seen[endpoint] = true
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return &LocalCDP{
+		endpoints: endpoints,
+		client:    httpClientOrDefault(opts.HTTPClient),
+		leased:    make(map[string]bool),
+	}
+}
+
+// Name returns ProviderLocalCDP
+func (l *LocalCDP) Name() BrowserProvider {
+	return ProviderLocalCDP
+}
+
+func (l *LocalCDP) configured() error {
+	if len(l.endpoints) == 0 {
+		return fmt.Errorf("%w: local-cdp needs at least one endpoint", ErrNotConfigured)
+	}
+	return nil
+}
+
+// MaxSessions is the number of endpoints; pools wait for a free endpoint
+// instead of sharing a browser between tasks
+func (l *LocalCDP) MaxSessions() int {
+	return len(l.endpoints)
+}
+
+// Create resolves the websocket debugger URL of the next free endpoint
+func (l *LocalCDP) Create(ctx context.Context) (*BrowserSession, error) {
+	if err := l.configured(); err != nil {
+		return nil, err
+	}
+
+	endpoint, err := l.lease()
+	if err != nil {
+		return nil, err
+	}
+	cdpURL, err := l.resolve(ctx, endpoint)
+	if err != nil {
+		l.release(endpoint)
+		return nil, err
+	}
+	return &BrowserSession{
+		CDPURL:    cdpURL,
+		SessionID: endpoint,
+		Provider:  ProviderLocalCDP,
+	}, nil
+}
+
+// Close frees the session's endpoint; the browser belongs to whoever runs it
+func (l *LocalCDP) Close(ctx context.Context, session *BrowserSession) error {
+	l.release(session.SessionID)
+	return nil
+}
+
+// lease claims the next endpoint without a live session, round-robin
+func (l *LocalCDP) lease() (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := 0; i < len(l.endpoints); i++ {
+		endpoint := l.endpoints[(l.next+i)%len(l.endpoints)]
+		if !l.leased[endpoint] {
+			l.leased[endpoint] = true
+			l.next = (l.next + i + 1) % len(l.endpoints)
+			return endpoint, nil
+		}
+	}
+	return "", fmt.Errorf("local-cdp: all %d endpoints are in use", len(l.endpoints))
+}
+
+func (l *LocalCDP) release(endpoint string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.leased, endpoint)
+}
+
+// Reset opens a blank tab, closes every other page and clears the cookies
+// and the storage of every origin the finished task left behind, so the next
+// task (or trial) does not inherit its logins. The endpoint serves no other
+// session, so every page belongs to the finished task.
+func (l *LocalCDP) Reset(ctx context.Context, session *BrowserSession) error {
+	base, err := devToolsURL(session.CDPURL)
+	if err != nil {
+		return err
+	}
+
+	var targets []struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	}
+	if err := doJSON(ctx, l.client, http.MethodGet, base+"/json/list", nil, nil, &targets); err != nil {
+		return fmt.Errorf("local-cdp list targets failed: %w", err)
+	}
+
+	var blank struct {
+		ID string `json:"id"`
+	}
+	if err := doJSON(ctx, l.client, http.MethodPut, base+"/json/new?about:blank", nil, nil, &blank); err != nil {
+		return fmt.Errorf("local-cdp open tab failed: %w", err)
+	}
+
+	for _, target := range targets {
+		if target.Type != "page" || target.ID == blank.ID {
+			continue
+		}
+		if err := doJSON(ctx, l.client, http.MethodGet, base+"/json/close/"+target.ID, nil, nil, nil); err != nil {
+			return fmt.Errorf("local-cdp close tab failed: %w", err)
+		}
+	}
+
+	pageURLs := make([]string, 0, len(targets))
+	for _, target := range targets {
+		pageURLs = append(pageURLs, target.URL)
+	}
+	if err := clearBrowserState(ctx, session.CDPURL, pageURLs); err != nil {
+		return fmt.Errorf("local-cdp clear browser state failed: %w", err)
+	}
+	return nil
+}
+
+// clearBrowserState deletes every cookie and the storage (local storage,
+// IndexedDB, caches, service workers) of the origins of pageURLs and of the
+// cookies' domains
+func clearBrowserState(ctx context.Context, cdpURL string, pageURLs []string) error {
+	conn, err := dialCDP(ctx, cdpURL)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var cookies struct {
+		Cookies []struct {
+			Domain string `json:"domain"`
+		} `json:"cookies"`
+	}
+	if err := conn.call(ctx, "Storage.getCookies", nil, &cookies); err != nil {
+		return err
+	}
+
+	origins := make(map[string]bool)
+	for _, pageURL := range pageURLs {
+		if u, err := url.Parse(pageURL); err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" {
+			origins[u.Scheme+"://"+u.Host] = true
+		}
+	}
+	for _, cookie := range cookies.Cookies {
+		if domain := strings.TrimPrefix(cookie.Domain, "."); domain != "" {
+			origins["http://"+domain] = true
+			origins["https://"+domain] = true
+		}
+	}
+
+	for _, origin := range slices.Sorted(maps.Keys(origins)) {
+		params := map[string]string{"origin": origin, "storageTypes": "all"}
+		if err := conn.call(ctx, "Storage.clearDataForOrigin", params, nil); err != nil {
+			return err
+		}
+	}
+	return conn.call(ctx, "Storage.clearCookies", nil, nil)
+}
+
+// HealthCheck resolves every endpoint
+func (l *LocalCDP) HealthCheck(ctx context.Context) error {
+	if err := l.configured(); err != nil {
+		return err
+	}
+	for _, endpoint := range l.endpoints {
+		if _, err := l.resolve(ctx, endpoint); err != nil {
+			return fmt.Errorf("local-cdp health check failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolve returns a ws:// endpoint as is and asks an http:// endpoint's
+// /json/version for its websocket debugger URL
+func (l *LocalCDP) resolve(ctx context.Context, endpoint string) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid local-cdp endpoint %q: %w", endpoint, err)
+	}
+
+	switch base.Scheme {
+	case "ws", "wss":
+		return endpoint, nil
+	case "http", "https":
+	default:
+		return "", fmt.Errorf("invalid local-cdp endpoint %q: unsupported scheme %s", endpoint, base.Scheme)
+	}
+
+	var version struct {
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	versionURL := strings.TrimSuffix(base.String(), "/") + "/json/version"
+	if err := doJSON(ctx, l.client, http.MethodGet, versionURL, nil, nil, &version); err != nil {
+		return "", fmt.Errorf("local-cdp resolve %s failed: %w", endpoint, err)
+	}
+	if version.WebSocketDebuggerURL == "" {
+		return "", fmt.Errorf("local-cdp resolve %s failed: no webSocketDebuggerUrl in /json/version", endpoint)
+	}
+
+	// Chrome reports the address it listens on, which is often localhost
+	// inside a container; point the URL at the host we actually reached
+	wsURL, err := url.Parse(version.WebSocketDebuggerURL)
+	if err != nil {
+		return "", fmt.Errorf("local-cdp resolve %s failed: %w", endpoint, err)
+	}
+	wsURL.Host = base.Host
+	if base.Scheme == "https" {
+		wsURL.Scheme = "wss"
+	}
+	return wsURL.String(), nil
+}
+
+// devToolsURL returns the DevTools HTTP endpoint serving a websocket debugger URL
+func devToolsURL(cdpURL string) (string, error) {
+	u, err := url.Parse(cdpURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid CDP URL %q: %w", cdpURL, err)
+	}
+	scheme := "http"
+	if u.Scheme == "wss" {
+		scheme = "https"
+	}
+	return scheme + "://" + u.Host, nil
+}
@@ -0,0 +1,181 @@
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestLocalCDPResolvesVersionEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json/version" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"Browser":"Chrome/120.0","webSocketDebuggerUrl":"ws://localhost:9222/devtools/browser/abc"}`))
+	}))
+	defer server.Close()
+
+	p := NewLocalCDP(LocalCDPOptions{Endpoints: []string{server.URL}})
+	session, err := p.Create(context.Background())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	if want := "ws://" + host + "/devtools/browser/abc"; session.CDPURL != want {
+		t.Errorf("Expected CDP URL %s, got %s", want, session.CDPURL)
+	}
+	if err := p.HealthCheck(context.Background()); err != nil {
+		t.Errorf("HealthCheck failed: %v", err)
+	}
+}
+
+func TestLocalCDPLeasesEndpoints(t *testing.T) {
+	p := NewLocalCDP(LocalCDPOptions{Endpoints: []string{"ws://a:9222/devtools/browser/1", " ", "ws://b:9222/devtools/browser/2", "ws://a:9222/devtools/browser/1"}})
+	ctx := context.Background()
+	if p.MaxSessions() != 2 {
+		t.Fatalf("Expected one session per distinct endpoint, got %d", p.MaxSessions())
+	}
+
+	first, err := p.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	second, err := p.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if first.CDPURL != "ws://a:9222/devtools/browser/1" || second.CDPURL != "ws://b:9222/devtools/browser/2" {
+		t.Errorf("Expected sessions on a then b, got %s and %s", first.CDPURL, second.CDPURL)
+	}
+	if _, err := p.Create(ctx); err == nil {
+		t.Error("Expected Create to fail while every endpoint has a live session")
+	}
+
+	p.Close(ctx, first)
+	third, err := p.Create(ctx)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if third.CDPURL != first.CDPURL {
+		t.Errorf("Expected the freed endpoint to be reused, got %s", third.CDPURL)
+	}
+}
+
+func TestLocalCDPUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	p := NewLocalCDP(LocalCDPOptions{Endpoints: []string{server.URL}})
+	if _, err := p.Create(context.Background()); err == nil {
+		t.Error("Expected Create to fail for an unreachable endpoint")
+	}
+	if err := p.HealthCheck(context.Background()); err == nil {
+		t.Error("Expected HealthCheck to fail for an unreachable endpoint")
+	}
+}
+
+// fakeDevTools serves the DevTools HTTP endpoints and a browser websocket
+// that records every CDP command
+type fakeDevTools struct {
+	mu       sync.Mutex
+	closed   []string
+	commands []string
+	cleared  []string
+}
+
+func (f *fakeDevTools) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/json/list":
+		w.Write([]byte(`[{"id":"page-1","type":"page","url":"https://shop.example/cart"},{"id":"worker-1","type":"service_worker","url":"https://shop.example/sw.js"},{"id":"page-2","type":"page","url":"chrome://newtab/"}]`))
+	case r.URL.Path == "/json/new" && r.Method == http.MethodPut:
+		w.Write([]byte(`{"id":"blank","type":"page"}`))
+	case strings.HasPrefix(r.URL.Path, "/json/close/"):
+		f.mu.Lock()
+		f.closed = append(f.closed, strings.TrimPrefix(r.URL.Path, "/json/close/"))
+		f.mu.Unlock()
+		w.Write([]byte("Target is closing"))
+	case r.URL.Path == "/devtools/browser/abc":
+		f.serveCDP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeDevTools) serveCDP(w http.ResponseWriter, r *http.Request) {
+	ws, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+	for {
+		var command struct {
+			ID     int               `json:"id"`
+			Method string            `json:"method"`
+			Params map[string]string `json:"params"`
+		}
+		if err := ws.ReadJSON(&command); err != nil {
+			return
+		}
+		f.mu.Lock()
+		f.commands = append(f.commands, command.Method)
+		if command.Method == "Storage.clearDataForOrigin" && command.Params["storageTypes"] == "all" {
+			f.cleared = append(f.cleared, command.Params["origin"])
+		}
+		f.mu.Unlock()
+
+		// Events arrive between replies and are skipped
+		ws.WriteJSON(map[string]any{"method": "Target.targetDestroyed", "params": map[string]string{}})
+		result := map[string]any{}
+		if command.Method == "Storage.getCookies" {
+			result["cookies"] = []map[string]string{{"domain": ".login.example"}}
+		}
+		ws.WriteJSON(map[string]any{"id": command.ID, "result": result})
+	}
+}
+
+func TestLocalCDPResetClearsBrowser(t *testing.T) {
+	devTools := &fakeDevTools{}
+	server := httptest.NewServer(devTools)
+	defer server.Close()
+
+	p := NewLocalCDP(LocalCDPOptions{})
+	session := &BrowserSession{CDPURL: "ws://" + strings.TrimPrefix(server.URL, "http://") + "/devtools/browser/abc"}
+	if err := p.Reset(context.Background(), session); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+
+	devTools.mu.Lock()
+	defer devTools.mu.Unlock()
+	if len(devTools.closed) != 2 || devTools.closed[0] != "page-1" || devTools.closed[1] != "page-2" {
+		t.Errorf("Expected both old pages to be closed, got %v", devTools.closed)
+	}
+	wantCleared := []string{"http://login.example", "https://login.example", "https://shop.example"}
+	if !slices.Equal(devTools.cleared, wantCleared) {
+		t.Errorf("Expected the storage of %v to be cleared, got %v", wantCleared, devTools.cleared)
+	}
+	if n := len(devTools.commands); n == 0 || devTools.commands[n-1] != "Storage.clearCookies" {
+		t.Errorf("Expected every cookie to be cleared last, got %v", devTools.commands)
+	}
+}
+
+func TestLocalCDPResetFailsWithoutCDP(t *testing.T) {
+	devTools := &fakeDevTools{}
+	server := httptest.NewServer(devTools)
+	defer server.Close()
+
+	// The tabs can be closed but the browser websocket is missing, so the
+	// session must not be handed to another task
+	p := NewLocalCDP(LocalCDPOptions{})
+	session := &BrowserSession{CDPURL: "ws://" + strings.TrimPrefix(server.URL, "http://") + "/devtools/browser/missing"}
+	if err := p.Reset(context.Background(), session); err == nil {
+		t.Error("Expected Reset to fail when browser state cannot be cleared")
+	}
+}
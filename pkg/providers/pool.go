@@ -0,0 +1,278 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Recycler is implemented by providers whose sessions can be reset and handed
+// to another task instead of being closed and replaced
+type Recycler interface {
+	Reset(ctx context.Context, session *BrowserSession) error
+}
+
+// SessionLimiter is implemented by providers that can only run a fixed number
+// of sessions at once; pools never exceed it
+type SessionLimiter interface {
+	MaxSessions() int
+}
+
+// PoolOptions configures a session pool
+type PoolOptions struct {
+	// Size is how many sessions the pool keeps warm or in use
+	Size int
+	// Demand is how many sessions the run will acquire, so the pool stops
+	// replacing sessions nobody will use (0 for unknown)
+	Demand int
+	// MaxSessions caps the provider's live sessions, idle ones included
+	// (0 for no limit); a SessionLimiter's own limit is applied on top
+	MaxSessions int
+}
+
+// Pool keeps warm sessions for one provider so tasks do not wait for a
+// session to be created, and enforces the provider's concurrency limit
+type Pool struct {
+	provider Provider
+	size     int
+	// remaining is the demand not yet acquired (-1 for unknown)
+	remaining int
+	idle      chan *BrowserSession
+	// slots holds one token per live session when MaxSessions is set
+	slots chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	inUse  int
+	closed bool
+}
+
+// NewPool creates an empty pool; call Warm to pre-create its sessions
+func NewPool(provider Provider, opts PoolOptions) *Pool {
+	if limiter, ok := provider.(SessionLimiter); ok {
+		if limit := limiter.MaxSessions(); limit > 0 && (opts.MaxSessions == 0 || limit < opts.MaxSessions) {
+			opts.MaxSessions = limit
+		}
+	}
+	size := opts.Size
+	if opts.MaxSessions > 0 && size > opts.MaxSessions {
+		size = opts.MaxSessions
+	}
+	if size < 0 {
+		size = 0
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		provider:  provider,
+		size:      size,
+		remaining: -1,
+		idle:      make(chan *BrowserSession, size),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	if opts.Demand > 0 {
+		p.remaining = opts.Demand
+	}
+	if opts.MaxSessions > 0 {
+		p.slots = make(chan struct{}, opts.MaxSessions)
+	}
+	return p
+}
+
+// Provider returns the pooled provider
+func (p *Pool) Provider() Provider {
+	return p.provider
+}
+
+// Warm creates sessions until the pool holds Size idle sessions. Sessions
+// that fail to start are reported but do not stop the others.
+func (p *Pool) Warm(ctx context.Context) error {
+	missing := p.size - len(p.idle)
+	errs := make(chan error, missing)
+	var wg sync.WaitGroup
+	for i := 0; i < missing; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- p.addIdle(ctx)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	var failures []error
+	for err := range errs {
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// Acquire returns a warm session, or creates one when none is idle. It waits
+// for a free slot when the provider is at its session limit.
+func (p *Pool) Acquire(ctx context.Context) (*BrowserSession, error) {
+	session, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.inUse++
+	if p.remaining > 0 {
+		p.remaining--
+	}
+	p.mu.Unlock()
+	return session, nil
+}
+
+func (p *Pool) acquire(ctx context.Context) (*BrowserSession, error) {
+	select {
+	case session := <-p.idle:
+		return session, nil
+	default:
+	}
+
+	if p.slots != nil {
+		select {
+		case session := <-p.idle:
+			return session, nil
+		case p.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return p.create(ctx)
+}
+
+// Release hands a session back. Reusable sessions of a Recycler are reset and
+// returned to the pool; others are closed and replaced in the background.
+func (p *Pool) Release(ctx context.Context, session *BrowserSession, reusable bool) error {
+	p.mu.Lock()
+	p.inUse--
+	p.mu.Unlock()
+
+	if reusable && !p.isClosed() {
+		if recycler, ok := p.provider.(Recycler); ok {
+			if err := recycler.Reset(ctx, session); err == nil && p.putIdle(session) {
+				return nil
+			}
+		}
+	}
+
+	err := p.close(ctx, session)
+	p.replenish()
+	return err
+}
+
+// Close stops replenishing and closes the idle sessions. Sessions still in
+// use are closed when they are released.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+	p.cancel()
+	p.wg.Wait()
+
+	var failures []error
+	for {
+		select {
+		case session := <-p.idle:
+			if err := p.close(ctx, session); err != nil {
+				failures = append(failures, err)
+			}
+		default:
+			return errors.Join(failures...)
+		}
+	}
+}
+
+// create starts a session; the caller holds a slot, which is freed on failure
+func (p *Pool) create(ctx context.Context) (*BrowserSession, error) {
+	session, err := p.provider.Create(ctx)
+	if err != nil {
+		p.freeSlot()
+		return nil, err
+	}
+	return session, nil
+}
+
+// addIdle creates one session for the idle list if a slot is free
+func (p *Pool) addIdle(ctx context.Context) error {
+	if !p.takeSlot() {
+		return nil
+	}
+	session, err := p.create(ctx)
+	if err != nil {
+		return fmt.Errorf("%s warm session failed: %w", p.provider.Name(), err)
+	}
+	if !p.putIdle(session) {
+		return p.close(context.WithoutCancel(ctx), session)
+	}
+	return nil
+}
+
+// replenish creates a session in the background after one is closed, keeping
+// Size sessions warm or in use
+func (p *Pool) replenish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed || len(p.idle)+p.inUse >= p.size || (p.remaining >= 0 && len(p.idle) >= p.remaining) {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		_ = p.addIdle(p.ctx)
+	}()
+}
+
+// putIdle adds a session to the idle list unless the pool is closed or full
+func (p *Pool) putIdle(session *BrowserSession) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	select {
+	case p.idle <- session:
+		return true
+	default:
+		return false
+	}
+}
+
+// close closes a session and frees its slot
+func (p *Pool) close(ctx context.Context, session *BrowserSession) error {
+	defer p.freeSlot()
+	return p.provider.Close(ctx, session)
+}
+
+// takeSlot claims a session slot without waiting
+func (p *Pool) takeSlot() bool {
+	if p.slots == nil {
+		return true
+	}
+	select {
+	case p.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Pool) freeSlot() {
+	if p.slots != nil {
+		<-p.slots
+	}
+}
+
+func (p *Pool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
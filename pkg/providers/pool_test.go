@@ -0,0 +1,207 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeProvider counts created and closed sessions
+type fakeProvider struct {
+	mu      sync.Mutex
+	created int
+	closed  int
+}
+
+func (f *fakeProvider) Name() BrowserProvider { return "fake" }
+
+func (f *fakeProvider) Create(ctx context.Context) (*BrowserSession, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.created++
+	return &BrowserSession{SessionID: fmt.Sprintf("s%d", f.created), Provider: "fake"}, nil
+}
+
+func (f *fakeProvider) Close(ctx context.Context, session *BrowserSession) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed++
+	return nil
+}
+
+func (f *fakeProvider) HealthCheck(ctx context.Context) error { return nil }
+
+func (f *fakeProvider) counts() (int, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.created, f.closed
+}
+
+// fakeRecycler is a fakeProvider whose sessions can be reset
+type fakeRecycler struct {
+	fakeProvider
+	resets int
+}
+
+func (f *fakeRecycler) Reset(ctx context.Context, session *BrowserSession) error {
+	f.resets++
+	return nil
+}
+
+func TestPoolWarmSessions(t *testing.T) {
+	provider := &fakeProvider{}
+	pool := NewPool(provider, PoolOptions{Size: 3})
+	ctx := context.Background()
+
+	if err := pool.Warm(ctx); err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	if created, _ := provider.counts(); created != 3 {
+		t.Fatalf("Expected 3 warm sessions, got %d", created)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := pool.Acquire(ctx); err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+	}
+	if created, _ := provider.counts(); created != 3 {
+		t.Errorf("Expected warm sessions to be reused, got %d created", created)
+	}
+
+	// The pool is empty, so the next session is created on demand
+	if _, err := pool.Acquire(ctx); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if created, _ := provider.counts(); created != 4 {
+		t.Errorf("Expected 4 sessions, got %d", created)
+	}
+}
+
+func TestPoolReplacesReleasedSessions(t *testing.T) {
+	provider := &fakeProvider{}
+	pool := NewPool(provider, PoolOptions{Size: 1, Demand: 2})
+	ctx := context.Background()
+	if err := pool.Warm(ctx); err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+
+	session, _ := pool.Acquire(ctx)
+	if err := pool.Release(ctx, session, true); err != nil {
+		t.Fatalf("Release failed: %v", err)
+	}
+	waitFor(t, func() bool { created, _ := provider.counts(); return created == 2 })
+
+	// Demand is met once the replacement is acquired, so it is not replaced
+	session, _ = pool.Acquire(ctx)
+	pool.Release(ctx, session, true)
+	if err := pool.Close(ctx); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if created, closed := provider.counts(); created != 2 || closed != 2 {
+		t.Errorf("Expected 2 sessions created and closed, got %d and %d", created, closed)
+	}
+}
+
+func TestPoolRecyclesSessions(t *testing.T) {
+	provider := &fakeRecycler{}
+	pool := NewPool(provider, PoolOptions{Size: 1})
+	ctx := context.Background()
+	pool.Warm(ctx)
+
+	first, _ := pool.Acquire(ctx)
+	pool.Release(ctx, first, true)
+	second, _ := pool.Acquire(ctx)
+	if second != first || provider.resets != 1 {
+		t.Errorf("Expected the reset session to be reused (resets=%d)", provider.resets)
+	}
+
+	// Sessions of failed tasks are not recycled
+	pool.Release(ctx, second, false)
+	pool.Close(ctx)
+	if created, closed := provider.counts(); closed != created {
+		t.Errorf("Expected every session to be closed, created %d closed %d", created, closed)
+	}
+}
+
+func TestPoolSessionLimit(t *testing.T) {
+	provider := &fakeProvider{}
+	pool := NewPool(provider, PoolOptions{Size: 2, MaxSessions: 1})
+	ctx := context.Background()
+	if err := pool.Warm(ctx); err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	if created, _ := provider.counts(); created != 1 {
+		t.Fatalf("Expected warming to stop at the session limit, got %d", created)
+	}
+
+	session, _ := pool.Acquire(ctx)
+
+	acquired := make(chan *BrowserSession)
+	go func() {
+		next, err := pool.Acquire(ctx)
+		if err != nil {
+			t.Errorf("Acquire failed: %v", err)
+		}
+		acquired <- next
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Acquire should wait while the provider is at its session limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	pool.Release(ctx, session, false)
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("Acquire did not resume after a session was released")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Acquire(timeoutCtx); err == nil {
+		t.Error("Expected Acquire to give up when its context ends")
+	}
+}
+
+// fakeLimited is a fakeProvider that can run a single session at a time
+type fakeLimited struct {
+	fakeProvider
+}
+
+func (f *fakeLimited) MaxSessions() int { return 1 }
+
+func TestPoolProviderSessionLimit(t *testing.T) {
+	provider := &fakeLimited{}
+	pool := NewPool(provider, PoolOptions{Size: 3, MaxSessions: 5})
+	ctx := context.Background()
+	if err := pool.Warm(ctx); err != nil {
+		t.Fatalf("Warm failed: %v", err)
+	}
+	if created, _ := provider.counts(); created != 1 {
+		t.Fatalf("Expected the provider's own limit to cap the pool, got %d sessions", created)
+	}
+
+	session, _ := pool.Acquire(ctx)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := pool.Acquire(timeoutCtx); err == nil {
+		t.Error("Expected Acquire to wait while the provider's only session is in use")
+	}
+	pool.Release(ctx, session, false)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
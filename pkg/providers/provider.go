@@ -0,0 +1,182 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BrowserProvider represents a browser service
+type BrowserProvider string
+
+const (
+	ProviderBrowserbase   BrowserProvider = "browserbase"
+	ProviderBrightdata    BrowserProvider = "brightdata"
+	ProviderHyperbrowser  BrowserProvider = "hyperbrowser"
+	ProviderAnchorBrowser BrowserProvider = "anchor"
+	ProviderLocalCDP      BrowserProvider = "local-cdp"
+)
+
+// BrowserSession contains CDP connection info
+type BrowserSession struct {
+	CDPURL    string
+	SessionID string
+	Provider  BrowserProvider
+}
+
+// Provider creates and releases browser sessions on one service
+type Provider interface {
+	Name() BrowserProvider
+	// Create starts a browser session and returns its CDP endpoint. A session
+	// without an endpoint is an error, so failover can try the next provider.
+	Create(ctx context.Context) (*BrowserSession, error)
+	// Close releases a session created by this provider
+	Close(ctx context.Context, session *BrowserSession) error
+	// HealthCheck verifies the provider is configured and its API accepts our credentials
+	HealthCheck(ctx context.Context) error
+}
+
+// ErrNotConfigured reports a provider without the credentials it needs
+var ErrNotConfigured = errors.New("provider not configured")
+
+// Registry holds the available providers keyed by name
+type Registry struct {
+	providers map[BrowserProvider]Provider
+}
+
+// NewRegistry creates a registry holding the given providers
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[BrowserProvider]Provider)}
+	for _, p := range providers {
+		r.Register(p)
+	}
+	return r
+}
+
+// Register adds a provider, replacing any provider with the same name
+func (r *Registry) Register(p Provider) {
+	r.providers[p.Name()] = p
+}
+
+// Get returns the named provider
+func (r *Registry) Get(name BrowserProvider) (Provider, error) {
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown browser provider: %s (available: %s)", name, r.names())
+	}
+	return p, nil
+}
+
+// Names returns the registered provider names in sorted order
+func (r *Registry) Names() []BrowserProvider {
+	names := make([]BrowserProvider, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+func (r *Registry) names() string {
+	var names []string
+	for _, name := range r.Names() {
+		names = append(names, string(name))
+	}
+	return strings.Join(names, ", ")
+}
+
+// defaultHTTPTimeout bounds each provider API call when no client is given
+const defaultHTTPTimeout = 30 * time.Second
+
+func httpClientOrDefault(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Timeout: defaultHTTPTimeout}
+}
+
+func baseURLOrDefault(baseURL, defaultURL string) string {
+	if baseURL != "" {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+	return defaultURL
+}
+
+// StatusError is a provider API response with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Body)
+}
+
+// doJSON sends a provider API request with an optional JSON body, fails on a
+// non-2xx status and decodes the JSON response into out when it is non-nil
+func doJSON(ctx context.Context, client *http.Client, method, url string, header map[string]string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("marshal request failed: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("request creation failed: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(snippet))}
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response failed: %w", err)
+	}
+	return nil
+}
+
+// checkAPI is a health check request: the API must respond and must not
+// reject the credentials. Other client errors (e.g. 404) still prove both.
+func checkAPI(ctx context.Context, client *http.Client, url string, header map[string]string) error {
+	err := doJSON(ctx, client, http.MethodGet, url, header, nil, nil)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden:
+			return fmt.Errorf("credentials rejected: %w", err)
+		case statusErr.StatusCode < 500:
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,278 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// recordedRequest is a request received by a provider stand-in
+type recordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]interface{}
+}
+
+// standIn is an httptest server that answers provider API calls by route
+type standIn struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+// route is a stand-in response: a status and JSON body
+type route struct {
+	status int
+	body   string
+}
+
+// newStandIn serves routes keyed by "METHOD /path"
+func newStandIn(t *testing.T, routes map[string]route) *standIn {
+	t.Helper()
+	s := &standIn{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := recordedRequest{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone()}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			_ = json.Unmarshal(data, &rec.Body)
+		}
+		s.mu.Lock()
+		s.requests = append(s.requests, rec)
+		s.mu.Unlock()
+
+		route, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(route.status)
+		io.WriteString(w, route.body)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// received returns the requests made to a route, oldest first
+func (s *standIn) received(key string) []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []recordedRequest
+	for _, req := range s.requests {
+		if req.Method+" "+req.Path == key {
+			matched = append(matched, req)
+		}
+	}
+	return matched
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry(
+		NewHyperbrowser(HyperbrowserOptions{}),
+		NewBrightdata(BrightdataOptions{}),
+	)
+
+	if _, err := registry.Get(ProviderBrightdata); err != nil {
+		t.Errorf("Get brightdata failed: %v", err)
+	}
+	if _, err := registry.Get("nope"); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+	names := registry.Names()
+	if len(names) != 2 || names[0] != ProviderBrightdata || names[1] != ProviderHyperbrowser {
+		t.Errorf("Unexpected names: %v", names)
+	}
+}
+
+func TestUnconfiguredProviders(t *testing.T) {
+	for _, p := range []Provider{
+		NewBrowserbase(BrowserbaseOptions{APIKey: "key"}),
+		NewBrightdata(BrightdataOptions{Username: "user"}),
+		NewHyperbrowser(HyperbrowserOptions{}),
+		NewAnchorBrowser(AnchorBrowserOptions{}),
+		NewLocalCDP(LocalCDPOptions{Endpoints: []string{""}}),
+	} {
+		if _, err := p.Create(context.Background()); !errors.Is(err, ErrNotConfigured) {
+			t.Errorf("%s: expected ErrNotConfigured from Create, got %v", p.Name(), err)
+		}
+		if err := p.HealthCheck(context.Background()); !errors.Is(err, ErrNotConfigured) {
+			t.Errorf("%s: expected ErrNotConfigured from HealthCheck, got %v", p.Name(), err)
+		}
+	}
+}
+
+func TestCheckAPI(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNotFound, false},
+		{http.StatusUnauthorized, true},
+		{http.StatusForbidden, true},
+		{http.StatusServiceUnavailable, true},
+	}
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		err := checkAPI(context.Background(), server.Client(), server.URL, nil)
+		server.Close()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Status %d: expected error=%v, got %v", tt.status, tt.wantErr, err)
+		}
+	}
+}
+
+// cloudProviderCase describes one API-backed provider for TestCloudProviders
+type cloudProviderCase struct {
+	name       string
+	provider   func(baseURL string) Provider
+	authHeader string
+	// create is the create-session route and its responses with and without a CDP URL
+	create, created, noCDPURL string
+	// wantCreate lists fields the create request body must carry
+	wantCreate map[string]interface{}
+	wantCDPURL string
+	// close is the route Close must hit for session "s-1"
+	close     string
+	wantClose map[string]interface{}
+	health    string
+}
+
+var cloudProviderCases = []cloudProviderCase{
+	{
+		name: "hyperbrowser",
+		provider: func(baseURL string) Provider {
+			return NewHyperbrowser(HyperbrowserOptions{APIKey: "key", BaseURL: baseURL})
+		},
+		authHeader: "x-api-key",
+		create:     "POST /api/session",
+		created:    `{"id":"s-1","wsEndpoint":"wss://connect.hyperbrowser.ai/s-1"}`,
+		noCDPURL:   `{"id":"s-1","wsEndpoint":""}`,
+		wantCreate: map[string]interface{}{"useStealth": true},
+		wantCDPURL: "wss://connect.hyperbrowser.ai/s-1",
+		close:      "PUT /api/session/s-1/stop",
+		health:     "GET /api/sessions",
+	},
+	{
+		name: "anchor",
+		provider: func(baseURL string) Provider {
+			return NewAnchorBrowser(AnchorBrowserOptions{APIKey: "key", BaseURL: baseURL})
+		},
+		authHeader: "anchor-api-key",
+		create:     "POST /v1/sessions",
+		created:    `{"data":{"id":"s-1","cdp_url":"wss://connect.anchorbrowser.io?sessionId=s-1"}}`,
+		noCDPURL:   `{"data":{"id":"s-1"}}`,
+		wantCreate: map[string]interface{}{
+			"browser": map[string]interface{}{"captcha_solver": map[string]interface{}{"active": true}},
+		},
+		wantCDPURL: "wss://connect.anchorbrowser.io?sessionId=s-1",
+		close:      "DELETE /v1/sessions/s-1",
+		health:     "GET /v1/sessions/all/status",
+	},
+	{
+		name: "browserbase",
+		provider: func(baseURL string) Provider {
+			return NewBrowserbase(BrowserbaseOptions{APIKey: "key", ProjectID: "proj", BaseURL: baseURL})
+		},
+		authHeader: "X-BB-API-Key",
+		create:     "POST /v1/sessions",
+		created:    `{"id":"s-1","connectUrl":"wss://connect.browserbase.com?sessionId=s-1"}`,
+		noCDPURL:   `{"id":"s-1","connectUrl":""}`,
+		wantCreate: map[string]interface{}{"projectId": "proj"},
+		wantCDPURL: "wss://connect.browserbase.com?sessionId=s-1",
+		close:      "POST /v1/sessions/s-1",
+		wantClose:  map[string]interface{}{"projectId": "proj", "status": "REQUEST_RELEASE"},
+		health:     "GET /v1/projects/proj",
+	},
+}
+
+func TestCloudProviders(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range cloudProviderCases {
+		t.Run(tc.name+"/create and close", func(t *testing.T) {
+			server := newStandIn(t, map[string]route{
+				tc.create: {http.StatusOK, tc.created},
+				tc.close:  {http.StatusOK, `{}`},
+			})
+			p := tc.provider(server.URL)
+
+			session, err := p.Create(ctx)
+			if err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			if session.CDPURL != tc.wantCDPURL || session.SessionID != "s-1" || session.Provider != p.Name() {
+				t.Errorf("Unexpected session: %+v", session)
+			}
+			create := server.received(tc.create)[0]
+			if create.Header.Get(tc.authHeader) != "key" {
+				t.Errorf("Create request missing %s header", tc.authHeader)
+			}
+			if !reflect.DeepEqual(create.Body, tc.wantCreate) {
+				t.Errorf("Expected create body %v, got %v", tc.wantCreate, create.Body)
+			}
+
+			if err := p.Close(ctx, session); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			closes := server.received(tc.close)
+			if len(closes) != 1 {
+				t.Fatalf("Expected one %s, got %d", tc.close, len(closes))
+			}
+			if closes[0].Header.Get(tc.authHeader) != "key" || !reflect.DeepEqual(closes[0].Body, tc.wantClose) {
+				t.Errorf("Unexpected close request: %+v", closes[0])
+			}
+		})
+
+		t.Run(tc.name+"/no CDP URL", func(t *testing.T) {
+			server := newStandIn(t, map[string]route{
+				tc.create: {http.StatusOK, tc.noCDPURL},
+				tc.close:  {http.StatusOK, `{}`},
+			})
+
+			if session, err := tc.provider(server.URL).Create(ctx); err == nil {
+				t.Fatalf("Expected Create to fail without a CDP URL, got %+v", session)
+			}
+			if len(server.received(tc.close)) != 1 {
+				t.Error("Expected the session without a CDP URL to be released")
+			}
+		})
+
+		t.Run(tc.name+"/error status", func(t *testing.T) {
+			server := newStandIn(t, map[string]route{
+				tc.create: {http.StatusInternalServerError, `{"error":"boom"}`},
+				tc.close:  {http.StatusInternalServerError, `{"error":"boom"}`},
+			})
+			p := tc.provider(server.URL)
+
+			var statusErr *StatusError
+			if _, err := p.Create(ctx); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError {
+				t.Errorf("Expected Create to fail with the 500, got %v", err)
+			}
+			if err := p.Close(ctx, &BrowserSession{SessionID: "s-1"}); err == nil {
+				t.Error("Expected Close to fail on 500")
+			}
+		})
+
+		t.Run(tc.name+"/health check", func(t *testing.T) {
+			for _, status := range []int{http.StatusOK, http.StatusUnauthorized} {
+				server := newStandIn(t, map[string]route{tc.health: {status, `{}`}})
+
+				err := tc.provider(server.URL).HealthCheck(ctx)
+				if wantErr := status != http.StatusOK; (err != nil) != wantErr {
+					t.Errorf("Status %d: expected error=%v, got %v", status, wantErr, err)
+				}
+				checks := server.received(tc.health)
+				if len(checks) != 1 || checks[0].Header.Get(tc.authHeader) != "key" {
+					t.Errorf("Status %d: expected one authenticated %s, got %+v", status, tc.health, checks)
+				}
+			}
+		})
+	}
+}
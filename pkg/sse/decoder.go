@@ -0,0 +1,136 @@
+// Package sse decodes text/event-stream (server-sent events) streams as
+// specified by the WHATWG HTML standard.
+package sse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultEventType is the type of events without an event field
+const DefaultEventType = "message"
+
+// Event is one dispatched server-sent event
+type Event struct {
+	// Type is the event field, or DefaultEventType when absent
+	Type string
+	// ID is the stream's last event ID when the event was dispatched
+	ID string
+	// Data is the event's data lines joined with "\n"
+	Data string
+}
+
+// Decoder reads events from an event stream. Lines may be of any length.
+type Decoder struct {
+	r *bufio.Reader
+
+	lastEventID string
+	retry       time.Duration
+	crPending   bool // the previous line ended in \r; skip a following \n
+}
+
+// NewDecoder returns a decoder reading from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReaderSize(r, 64*1024)}
+}
+
+// Decode returns the next event. It returns io.EOF at the end of the stream;
+// an incomplete event at the end of the stream is discarded, per the spec.
+func (d *Decoder) Decode() (Event, error) {
+	var event Event
+	var data strings.Builder
+	hasData := false
+
+	for {
+		line, err := d.readLine()
+		if err != nil {
+			return Event{}, err
+		}
+
+		// A blank line dispatches the event; events without data are dropped
+		if len(line) == 0 {
+			if !hasData {
+				event = Event{}
+				continue
+			}
+			event.ID = d.lastEventID
+			event.Data = strings.TrimSuffix(data.String(), "\n")
+			if event.Type == "" {
+				event.Type = DefaultEventType
+			}
+			return event, nil
+		}
+
+		// Lines starting with a colon are comments
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value, found := bytes.Cut(line, []byte(":"))
+		if found {
+			value = bytes.TrimPrefix(value, []byte(" "))
+		}
+
+		switch string(field) {
+		case "event":
+			event.Type = string(value)
+		case "data":
+			data.Write(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !bytes.Contains(value, []byte{0}) {
+				d.lastEventID = string(value)
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(string(value), 10, 63); err == nil {
+				d.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// LastEventID returns the most recent id field, to send as Last-Event-ID on reconnect
+func (d *Decoder) LastEventID() string {
+	return d.lastEventID
+}
+
+// Retry returns the reconnection time from the most recent retry field (0 if none)
+func (d *Decoder) Retry() time.Duration {
+	return d.retry
+}
+
+// readLine reads one line ending in \r\n, \n or \r, without the terminator
+func (d *Decoder) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		buf, err := d.r.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		buf, _ = d.r.Peek(d.r.Buffered())
+
+		if d.crPending {
+			d.crPending = false
+			if buf[0] == '\n' {
+				d.r.Discard(1)
+				continue
+			}
+		}
+
+		i := bytes.IndexAny(buf, "\r\n")
+		if i < 0 {
+			line = append(line, buf...)
+			d.r.Discard(len(buf))
+			continue
+		}
+		line = append(line, buf[:i]...)
+		d.crPending = buf[i] == '\r'
+		d.r.Discard(i + 1)
+		return line, nil
+	}
+}
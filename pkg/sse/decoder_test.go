@@ -0,0 +1,164 @@
+package sse
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeAll reads every event from a stream
+func decodeAll(t *testing.T, d *Decoder) []Event {
+	t.Helper()
+	var events []Event
+	for {
+		event, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return events
+		}
+		if err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		events = append(events, event)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	large := strings.Repeat("x", 1<<20)
+
+	tests := []struct {
+		name   string
+		stream string
+		want   []Event
+	}{
+		{
+			name:   "single data line",
+			stream: "data: hello\n\n",
+			want:   []Event{{Type: "message", Data: "hello"}},
+		},
+		{
+			name:   "event and id fields",
+			stream: "event: content\nid: 7\ndata: {\"a\":1}\n\n",
+			want:   []Event{{Type: "content", ID: "7", Data: `{"a":1}`}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: first\ndata: second\ndata:\n\n",
+			want:   []Event{{Type: "message", Data: "first\nsecond\n"}},
+		},
+		{
+			name:   "CRLF and CR line endings",
+			stream: "event: a\r\ndata: 1\r\n\r\nevent: b\rdata: 2\r\r",
+			want:   []Event{{Type: "a", Data: "1"}, {Type: "b", Data: "2"}},
+		},
+		{
+			name:   "comments and unknown fields are ignored",
+			stream: ": keep-alive\nfoo: bar\ndata: x\n\n",
+			want:   []Event{{Type: "message", Data: "x"}},
+		},
+		{
+			name:   "no space after colon and field without colon",
+			stream: "data:tight\ndata\n\n",
+			want:   []Event{{Type: "message", Data: "tight\n"}},
+		},
+		{
+			name:   "only the first space is stripped",
+			stream: "data:  two spaces\n\n",
+			want:   []Event{{Type: "message", Data: " two spaces"}},
+		},
+		{
+			name:   "event without data is not dispatched and resets the type",
+			stream: "event: ping\nid: 1\n\ndata: x\n\n",
+			want:   []Event{{Type: "message", ID: "1", Data: "x"}},
+		},
+		{
+			name:   "last event ID persists across events",
+			stream: "id: 5\ndata: a\n\ndata: b\n\nid:\ndata: c\n\n",
+			want: []Event{
+				{Type: "message", ID: "5", Data: "a"},
+				{Type: "message", ID: "5", Data: "b"},
+				{Type: "message", ID: "", Data: "c"},
+			},
+		},
+		{
+			name:   "id containing NUL is ignored",
+			stream: "id: 1\ndata: a\n\nid: 2\x003\ndata: b\n\n",
+			want: []Event{
+				{Type: "message", ID: "1", Data: "a"},
+				{Type: "message", ID: "1", Data: "b"},
+			},
+		},
+		{
+			name:   "incomplete event at end of stream is discarded",
+			stream: "data: a\n\ndata: partial\n",
+			want:   []Event{{Type: "message", Data: "a"}},
+		},
+		{
+			name:   "line longer than 64KB",
+			stream: "event: tool_use_parameter_streaming_complete\ndata: " + large + "\n\n",
+			want:   []Event{{Type: "tool_use_parameter_streaming_complete", Data: large}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeAll(t, NewDecoder(strings.NewReader(tt.stream)))
+			if !reflect.DeepEqual(got, tt.want) {
+				if len(got) == len(tt.want) && len(got) == 1 && len(got[0].Data) > 100 {
+					t.Fatalf("Large event mismatch: got type %q with %d bytes of data", got[0].Type, len(got[0].Data))
+				}
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDecodeRetry(t *testing.T) {
+	tests := []struct {
+		stream string
+		want   time.Duration
+	}{
+		{"retry: 3000\n\n", 3 * time.Second},
+		{"retry: 10\nretry: abc\n\n", 10 * time.Millisecond},
+		{"retry: -5\n\n", 0},
+	}
+	for _, tt := range tests {
+		d := NewDecoder(strings.NewReader(tt.stream))
+		decodeAll(t, d)
+		if d.Retry() != tt.want {
+			t.Errorf("Stream %q: expected retry %s, got %s", tt.stream, tt.want, d.Retry())
+		}
+	}
+}
+
+func TestDecodeRecordedMixStream(t *testing.T) {
+	f, err := os.Open("testdata/mix_session.sse")
+	if err != nil {
+		t.Fatalf("Open recording failed: %v", err)
+	}
+	defer f.Close()
+
+	d := NewDecoder(f)
+	events := decodeAll(t, d)
+
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	want := []string{
+		"connected", "thinking", "tool_use_start", "tool_use_parameter_streaming_complete",
+		"tool_execution_start", "heartbeat", "tool_execution_complete", "content", "complete",
+	}
+	if !reflect.DeepEqual(types, want) {
+		t.Errorf("Expected event types %v, got %v", want, types)
+	}
+	if d.LastEventID() != "9" || events[len(events)-1].ID != "9" {
+		t.Errorf("Expected last event ID 9, got %q", d.LastEventID())
+	}
+	if d.Retry() != 3*time.Second {
+		t.Errorf("Expected retry 3s, got %s", d.Retry())
+	}
+}